@@ -198,10 +198,13 @@ func seedCourses() {
 func seedQuestions() {
 	questions := []models.Question{
 		{
-			ID:          "q1",
+			ID:          "ceg543-23-24-e1",
 			CourseID:    "CEG543",
 			SessionID:   "23-24",
-			ImageLinks:  []string{"https://example.com/image1.jpg", "https://example.com/image2.jpg"},
+			Images: []models.QuestionImage{
+				{Page: 1, ImageAsset: models.ImageAsset{URL: "https://example.com/image1.jpg", Format: "jpg"}},
+				{Page: 2, ImageAsset: models.ImageAsset{URL: "https://example.com/image2.jpg", Format: "jpg"}},
+			},
 			Lecturer:    stringPtr("Prof. Johnson"),
 			TimeAllowed: intPtr(180),
 			Tips:        stringPtr("Focus on beam analysis"),
@@ -212,10 +215,10 @@ func seedQuestions() {
 			UploaderID:  stringPtr("user1"),
 		},
 		{
-			ID:          "q2",
+			ID:          "eee321-23-24-t1",
 			CourseID:    "EEE321",
 			SessionID:   "23-24",
-			ImageLinks:  []string{"https://example.com/image3.jpg"},
+			Images:      []models.QuestionImage{{Page: 1, ImageAsset: models.ImageAsset{URL: "https://example.com/image3.jpg", Format: "jpg"}}},
 			Lecturer:    stringPtr("Dr. Williams"),
 			TimeAllowed: intPtr(120),
 			Type:        models.QuestionTypeTest,
@@ -225,7 +228,7 @@ func seedQuestions() {
 			UploaderID:  stringPtr("user2"),
 		},
 		{
-			ID:        "q3",
+			ID:        "csc412-24-25-e1",
 			CourseID:  "CSC412",
 			SessionID: "24-25",
 			Type:      models.QuestionTypeExam,
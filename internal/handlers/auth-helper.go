@@ -128,6 +128,30 @@ func (h *AuthHelper) GetCurrentUserRole(c *gin.Context) (string, error) {
 	return role, nil
 }
 
+// currentUser extracts both the user ID and role from authenticated context
+func currentUser(c *gin.Context) (string, string, error) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		return "", "", err
+	}
+
+	role, err := Auth.GetCurrentUserRole(c)
+	if err != nil {
+		return "", "", err
+	}
+
+	return userID, role, nil
+}
+
+// optionalUser returns the user ID and role set by OptionalAuth, or empty strings for anonymous requests
+func optionalUser(c *gin.Context) (string, string) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("userRole")
+	id, _ := userID.(string)
+	r, _ := role.(string)
+	return id, r
+}
+
 func (h *AuthHelper) CustomRecovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
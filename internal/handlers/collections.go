@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookmarkQuestion handles saving a question to the user's bookmarks
+func BookmarkQuestion(c *gin.Context) {
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	bookmark, err := services.BookmarkQuestion(c.Param("id"), userID, role)
+	Res.Send(c, bookmark, err, "Question bookmarked successfully")
+}
+
+// RemoveBookmark handles removing a question from the user's bookmarks
+func RemoveBookmark(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	err = services.RemoveBookmark(c.Param("id"), userID)
+	Res.Send(c, nil, err, "Bookmark removed successfully")
+}
+
+// GetMyBookmarks handles listing the current user's bookmarked questions
+func GetMyBookmarks(c *gin.Context) {
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	bookmarks, meta, err := services.GetBookmarks(userID, role, page, limit)
+	Res.Page(c, bookmarks, meta, err)
+}
+
+// GetMyCollections handles listing the current user's collections
+func GetMyCollections(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	collections, meta, err := services.GetMyCollections(userID, page, limit)
+	Res.Page(c, collections, meta, err)
+}
+
+// CreateCollection handles creating an empty collection
+func CreateCollection(c *gin.Context) {
+	var input models.CreateCollectionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	collection, err := services.CreateCollection(userID, input)
+	Res.Created(c, collection, err)
+}
+
+// GetCollection handles reading a collection; shared ones are readable by anyone
+func GetCollection(c *gin.Context) {
+	userID, role := optionalUser(c)
+
+	collection, err := services.GetCollection(c.Param("id"), userID, role)
+	Res.Send(c, collection, err)
+}
+
+// UpdateCollection handles renaming, describing or sharing a collection
+func UpdateCollection(c *gin.Context) {
+	var input models.UpdateCollectionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	collection, err := services.UpdateCollection(c.Param("id"), userID, input)
+	Res.Send(c, collection, err, "Collection updated successfully")
+}
+
+// DeleteCollection handles deleting a collection
+func DeleteCollection(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	err = services.DeleteCollection(c.Param("id"), userID)
+	Res.Send(c, nil, err, "Collection deleted successfully")
+}
+
+// AddCollectionItem handles appending a question to a collection
+func AddCollectionItem(c *gin.Context) {
+	var input models.AddCollectionItemDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	collection, err := services.AddCollectionItem(c.Param("id"), userID, role, input)
+	Res.Send(c, collection, err, "Question added to collection")
+}
+
+// RemoveCollectionItem handles taking a question out of a collection
+func RemoveCollectionItem(c *gin.Context) {
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	collection, err := services.RemoveCollectionItem(c.Param("id"), userID, role, c.Param("questionId"))
+	Res.Send(c, collection, err, "Question removed from collection")
+}
+
+// ReorderCollection handles setting a new order for a collection's questions
+func ReorderCollection(c *gin.Context) {
+	var input models.ReorderCollectionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	collection, err := services.ReorderCollection(c.Param("id"), userID, role, input)
+	Res.Send(c, collection, err, "Collection reordered successfully")
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetComments handles listing the top-level comments of a question
+func GetComments(c *gin.Context) {
+	userID, role := optionalUser(c)
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	comments, meta, err := services.GetComments(c.Param("id"), userID, role, page, limit)
+	Res.Page(c, comments, meta, err)
+}
+
+// GetCommentReplies handles listing the replies in a comment thread
+func GetCommentReplies(c *gin.Context) {
+	commentID, err := parseIntID(c, "commentId")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role := optionalUser(c)
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	replies, meta, err := services.GetReplies(c.Param("id"), uint(commentID), userID, role, page, limit)
+	Res.Page(c, replies, meta, err)
+}
+
+// CreateComment handles starting a thread or replying to one
+func CreateComment(c *gin.Context) {
+	var input models.CreateCommentDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	comment, err := services.CreateComment(c.Param("id"), userID, input)
+	Res.Created(c, comment, err)
+}
+
+// UpdateComment handles the author editing their comment
+func UpdateComment(c *gin.Context) {
+	commentID, err := parseIntID(c, "commentId")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	var input models.UpdateCommentDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	comment, err := services.UpdateComment(c.Param("id"), uint(commentID), userID, input)
+	Res.Send(c, comment, err, "Comment updated successfully")
+}
+
+// DeleteComment handles removal of a comment by its author or an admin
+func DeleteComment(c *gin.Context) {
+	commentID, err := parseIntID(c, "commentId")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	err = services.DeleteComment(c.Param("id"), uint(commentID), userID, role)
+	Res.Send(c, nil, err, "Comment deleted successfully")
+}
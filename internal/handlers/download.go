@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"qb/internal/services"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DownloadQuestion handles streaming all pages of a question as a ZIP or a single PDF
+func DownloadQuestion(c *gin.Context) {
+	format := c.DefaultQuery("format", services.DownloadFormatZIP)
+	if format != services.DownloadFormatZIP && format != services.DownloadFormatPDF {
+		Res.Invalid(c, "format must be one of: zip pdf")
+		return
+	}
+
+	userID, role := optionalUser(c)
+	question, err := services.GetDownloadableQuestion(c.Param("id"), userID, role)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	filename := fmt.Sprintf("%s.%s", question.ID, format)
+
+	switch format {
+	case services.DownloadFormatPDF:
+		// Rendered up front so failures can still be reported as JSON
+		pdf, err := services.BuildQuestionPDF(question)
+		if err != nil {
+			Res.Send(c, nil, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Data(200, "application/pdf", pdf.Bytes())
+
+	case services.DownloadFormatZIP:
+		c.Header("Content-Type", "application/zip")
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Status(200)
+
+		// Headers are already sent, so a failure can only cut the archive short
+		if err := services.WriteQuestionZip(c.Writer, question); err != nil {
+			log.Printf("Download of %s aborted: %v", question.ID, err)
+			c.Abort()
+			return
+		}
+	}
+
+	if question.Approved {
+		services.IncrementDownloads(userID, question.ID)
+	}
+}
+
+// PrintQuestion handles serving a print-ready PDF with a cover page and tips appendix
+func PrintQuestion(c *gin.Context) {
+	userID, role := optionalUser(c)
+	question, err := services.GetDownloadableQuestion(c.Param("id"), userID, role)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	file, err := services.GetPrintablePDF(question)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+	defer file.Close()
+
+	var modified time.Time
+	if info, err := file.Stat(); err == nil {
+		modified = info.ModTime()
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s-print.pdf"`, question.ID))
+	http.ServeContent(c.Writer, c.Request, question.ID+"-print.pdf", modified, file)
+
+	if question.Approved {
+		services.IncrementDownloads(userID, question.ID)
+	}
+}
+
+// GetCourseArchive handles streaming every approved paper of a course as a single ZIP
+func GetCourseArchive(c *gin.Context) {
+	course, err := services.GetArchivableCourse(c.Param("id"))
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-archive.zip"`, strings.ToLower(course.ID)))
+	c.Status(200)
+
+	// Headers are already sent, so a failure can only cut the archive short
+	if err := services.WriteCourseArchive(c.Writer, course); err != nil {
+		log.Printf("Archive of %s aborted: %v", course.ID, err)
+		c.Abort()
+		return
+	}
+
+	questionIDs := make([]string, len(course.Questions))
+	for i, question := range course.Questions {
+		questionIDs[i] = question.ID
+	}
+	userID, _ := optionalUser(c)
+	services.IncrementDownloads(userID, questionIDs...)
+}
+
+// RedirectToFile handles reading a stored page by redirecting to a short-lived presigned link
+func RedirectToFile(c *gin.Context) {
+	url, err := services.PresignedFileURL(strings.TrimPrefix(c.Param("key"), "/"))
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	// The link expires, so clients must come back here rather than cache it
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, url)
+}
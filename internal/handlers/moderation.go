@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPendingQuestions handles listing the moderation queue
+func GetPendingQuestions(c *gin.Context) {
+	courseID := c.Query("courseId")
+	sessionID := c.Query("sessionId")
+	uploaderID := c.Query("uploaderId")
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	questions, err := services.GetPendingQuestions(courseID, sessionID, uploaderID, page, limit)
+	Res.Send(c, questions, err)
+}
+
+// ApproveQuestion handles approving a pending question
+func ApproveQuestion(c *gin.Context) {
+	var input models.ModerateQuestionDTO
+	if err := bindOptionalJSON(c, &input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	adminID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	question, err := services.ApproveQuestion(c.Param("id"), adminID, input)
+	Res.Send(c, question, err, "Question approved successfully")
+}
+
+// RejectQuestion handles rejecting a question with a reason
+func RejectQuestion(c *gin.Context) {
+	var input models.ModerateQuestionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	adminID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	question, err := services.RejectQuestion(c.Param("id"), adminID, input)
+	Res.Send(c, question, err, "Question rejected successfully")
+}
+
+// GetMyQuestions handles listing the authenticated user's uploads with their moderation status
+func GetMyQuestions(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	questions, err := services.GetMyQuestions(userID, page, limit)
+	Res.Send(c, questions, err)
+}
+
+// GetModerationHistory handles listing a question's moderation events for its uploader or an admin
+func GetModerationHistory(c *gin.Context) {
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	events, err := services.GetModerationHistory(c.Param("id"), userID, role)
+	Res.Send(c, events, err)
+}
+
+// bindOptionalJSON binds a JSON body when one is sent and leaves the input zeroed otherwise
+func bindOptionalJSON(c *gin.Context, input interface{}) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+	return c.ShouldBindJSON(input)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReorderQuestionImages handles setting a new page order for a question
+func ReorderQuestionImages(c *gin.Context) {
+	var input models.ReorderImagesDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	question, err := services.ReorderQuestionImages(c.Param("id"), userID, role, input)
+	Res.Send(c, question, err, "Images reordered successfully")
+}
+
+// RemoveQuestionImage handles removing a single page by its image ID
+func RemoveQuestionImage(c *gin.Context) {
+	imageID, err := parseIntID(c, "imageId")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	question, err := services.RemoveQuestionImage(c.Param("id"), userID, role, uint(imageID))
+	Res.Send(c, question, err, "Image removed successfully")
+}
+
+// ReplaceQuestionImage handles replacing a single page with a staged upload
+func ReplaceQuestionImage(c *gin.Context) {
+	imageID, err := parseIntID(c, "imageId")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	var input models.ReplaceImageDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	question, err := services.ReplaceQuestionImage(c.Param("id"), userID, role, uint(imageID), input)
+	Res.Send(c, question, err, "Image replaced successfully")
+}
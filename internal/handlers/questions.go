@@ -7,24 +7,38 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// GetQuestions handles retrieving questions with optional filtering
+// GetQuestions handles retrieving questions with filtering, sorting and pagination
 func GetQuestions(c *gin.Context) {
-	// Extract query parameters
-	courseID := c.Query("courseId")
-	sessionID := c.Query("sessionId")
-	questionType := c.Query("type")
-	page := services.GetIntQuery(c.Query("page"), 1)
-	limit := services.GetIntQuery(c.Query("limit"), 20)
-
-	questions, err := services.GetQuestions(courseID, sessionID, questionType, page, limit)
+	var filter models.QuestionFilterDTO
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role := optionalUser(c)
+	questions, meta, err := services.GetQuestions(filter, userID, role)
+	Res.Page(c, questions, meta, err)
+}
+
+// GetTrendingQuestions handles ranking the questions the user can see by recent engagement
+func GetTrendingQuestions(c *gin.Context) {
+	var filter models.TrendingFilterDTO
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role := optionalUser(c)
+	questions, err := services.GetTrendingQuestions(filter, userID, role)
 	Res.Send(c, questions, err)
 }
 
 // GetQuestionByID handles retrieving a single question by ID
 func GetQuestionByID(c *gin.Context) {
 	id := c.Param("id")
+	userID, role := optionalUser(c)
 	
-	question, err := services.GetQuestionByID(id)
+	question, err := services.GetQuestionByID(id, userID, role, c.ClientIP())
 	Res.Send(c, question, err)
 }
 
@@ -64,6 +78,36 @@ func CreateQuestion(c *gin.Context) {
 	Res.Send(c, response, nil)
 }
 
+// UpdateQuestion handles partial updates of question metadata by the uploader or an admin
+func UpdateQuestion(c *gin.Context) {
+	var input models.UpdateQuestionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	question, err := services.UpdateQuestion(c.Param("id"), userID, role, input)
+	Res.Send(c, question, err, "Question updated successfully")
+}
+
+// DeleteQuestion handles deleting a question and its images by the uploader or an admin
+func DeleteQuestion(c *gin.Context) {
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	err = services.DeleteQuestion(c.Param("id"), userID, role)
+	Res.Send(c, gin.H{"deleted": true}, err, "Question deleted successfully")
+}
+
 // Helper functions
 func extractTempPublicIDs(uploadResults *models.UploadResponse) []string {
 	if uploadResults == nil {
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateQuestion handles setting or changing the user's rating of a question
+func RateQuestion(c *gin.Context) {
+	var input models.RateQuestionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	rating, err := services.RateQuestion(c.Param("id"), userID, input)
+	Res.Send(c, rating, err, "Rating saved successfully")
+}
+
+// RemoveRating handles withdrawing the user's rating of a question
+func RemoveRating(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	rating, err := services.RemoveRating(c.Param("id"), userID)
+	Res.Send(c, rating, err, "Rating removed successfully")
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportQuestion handles a user reporting a problem with a question
+func ReportQuestion(c *gin.Context) {
+	var input models.CreateReportDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	report, err := services.CreateReport(c.Param("id"), userID, input)
+	Res.Created(c, report, err)
+}
+
+// GetReports handles listing the admin report queue
+func GetReports(c *gin.Context) {
+	status := c.Query("status")
+	questionID := c.Query("questionId")
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	reports, err := services.GetReports(status, questionID, page, limit)
+	Res.Send(c, reports, err)
+}
+
+// ResolveReport handles closing a report that led to a fix
+func ResolveReport(c *gin.Context) {
+	id, err := parseIntID(c, "id")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	var input models.ReviewReportDTO
+	if err := bindOptionalJSON(c, &input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	adminID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	report, err := services.ResolveReport(uint(id), adminID, input)
+	Res.Send(c, report, err, "Report resolved successfully")
+}
+
+// DismissReport handles closing a report that needed no action
+func DismissReport(c *gin.Context) {
+	id, err := parseIntID(c, "id")
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	var input models.ReviewReportDTO
+	if err := bindOptionalJSON(c, &input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	adminID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	report, err := services.DismissReport(uint(id), adminID, input)
+	Res.Send(c, report, err, "Report dismissed successfully")
+}
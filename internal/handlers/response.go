@@ -40,6 +40,21 @@ func (h *ResponseHelper) Send(c *gin.Context, data interface{}, err error, messa
 	})
 }
 
+// Page handles paginated listings, carrying the pagination metadata alongside the data
+func (h *ResponseHelper) Page(c *gin.Context, data interface{}, meta *models.PaginationMeta, err error) {
+	if err != nil {
+		h.sendError(c, err)
+		return
+	}
+
+	c.JSON(200, models.APIResponse{
+		Code:    200,
+		Message: "success",
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 // Created handles 201 created responses
 func (h *ResponseHelper) Created(c *gin.Context, data interface{}, err error) {
 	if err != nil {
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"qb/internal/services"
+	"qb/pkg/models"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tusVersion is the only version of the tus protocol the upload endpoint speaks
+const tusVersion = "1.0.0"
+
+// TusHeaders marks every response of the upload endpoint as tus and rejects clients speaking another version
+func TusHeaders() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", tusVersion)
+
+		if c.Request.Method != http.MethodOptions && c.GetHeader("Tus-Resumable") != tusVersion {
+			c.Header("Tus-Version", tusVersion)
+			c.AbortWithStatus(http.StatusPreconditionFailed)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// TusOptions handles tus discovery of the supported version, extensions and size limit
+func TusOptions(c *gin.Context) {
+	c.Header("Tus-Version", tusVersion)
+	c.Header("Tus-Extension", "creation,termination,expiration")
+	c.Header("Tus-Max-Size", strconv.Itoa(services.ResumableMaxSize))
+	c.Status(http.StatusNoContent)
+}
+
+// CreateResumableUpload handles tus creation. Upload-Metadata may carry filename and, to add the file
+// to an earlier request, requestId; the request ID is returned in the Upload-Request-Id header.
+func CreateResumableUpload(c *gin.Context) {
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		Res.Invalid(c, "Upload-Length header is required")
+		return
+	}
+
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	upload, err := services.CreateResumableUpload(userID, length, metadata["filename"], metadata["requestId"])
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/%s", strings.TrimSuffix(c.FullPath(), "/"), upload.ID))
+	c.Header("Upload-Request-Id", upload.RequestID)
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+}
+
+// GetResumableUploadOffset handles tus HEAD requests reporting how much of an upload arrived
+func GetResumableUploadOffset(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	upload, err := services.GetResumableUpload(c.Param("id"), userID)
+	if err != nil {
+		// HEAD responses carry no body, so only the status is sent
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	setUploadHeaders(c, upload)
+	c.Status(http.StatusOK)
+}
+
+// AppendResumableUpload handles tus PATCH requests carrying the next chunk of an upload
+func AppendResumableUpload(c *gin.Context) {
+	if c.ContentType() != "application/offset+octet-stream" {
+		c.JSON(http.StatusUnsupportedMediaType, models.APIResponse{
+			Code:    http.StatusUnsupportedMediaType,
+			Message: "Content-Type must be application/offset+octet-stream",
+		})
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil {
+		Res.Invalid(c, "Upload-Offset header is required")
+		return
+	}
+
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	upload, err := services.AppendResumableUpload(c.Param("id"), userID, offset, c.Request.Body)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	setUploadHeaders(c, upload)
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteResumableUpload handles tus termination of an upload
+func DeleteResumableUpload(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	if err := services.DeleteResumableUpload(c.Param("id"), userID); err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetResumableRequest handles reading a request's files as upload results for CreateQuestion
+func GetResumableRequest(c *gin.Context) {
+	userID, err := Auth.GetCurrentUserID(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	response, err := services.GetResumableRequest(c.Param("requestId"), userID)
+	Res.Send(c, response, err)
+}
+
+// setUploadHeaders reports an upload's progress, and its staged public ID once it is finished
+func setUploadHeaders(c *gin.Context, upload *models.ResumableUpload) {
+	c.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Header("Upload-Request-Id", upload.RequestID)
+	if upload.PublicID != nil {
+		c.Header("Upload-Public-Id", *upload.PublicID)
+	}
+}
+
+// parseUploadMetadata decodes the tus Upload-Metadata header: comma-separated keys, each followed
+// by an optional space and base64 value
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if key == "" || err != nil {
+			return nil, fmt.Errorf("invalid Upload-Metadata entry %q", pair)
+		}
+		metadata[key] = string(value)
+	}
+
+	return metadata, nil
+}
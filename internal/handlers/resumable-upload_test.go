@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{"empty header", "", map[string]string{}, false},
+		{"blank header", "   ", map[string]string{}, false},
+		{"filename", "filename cGFnZTEucG5n", map[string]string{"filename": "page1.png"}, false},
+		{"filename and request", "filename cGFnZTEucG5n,requestId YWJj", map[string]string{"filename": "page1.png", "requestId": "abc"}, false},
+		{"spaces after commas", "filename cGFnZTEucG5n, requestId YWJj", map[string]string{"filename": "page1.png", "requestId": "abc"}, false},
+		{"key without value", "is_confidential", map[string]string{"is_confidential": ""}, false},
+		{"invalid base64", "filename !!!", nil, true},
+		{"empty entry", "filename cGFnZTEucG5n,,", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUploadMetadata(tt.header)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseUploadMetadata(%q) = %v, want an error", tt.header, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseUploadMetadata(%q): %v", tt.header, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("parseUploadMetadata(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTusHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.OPTIONS("/uploads", TusHeaders(), TusOptions)
+	router.POST("/uploads", TusHeaders(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	tests := []struct {
+		name       string
+		method     string
+		version    string
+		wantStatus int
+	}{
+		{"discovery needs no version", http.MethodOptions, "", http.StatusNoContent},
+		{"supported version", http.MethodPost, "1.0.0", http.StatusCreated},
+		{"missing version", http.MethodPost, "", http.StatusPreconditionFailed},
+		{"other version", http.MethodPost, "0.2.2", http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/uploads", nil)
+			if tt.version != "" {
+				req.Header.Set("Tus-Resumable", tt.version)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Tus-Resumable"); got != tusVersion {
+				t.Fatalf("Tus-Resumable = %q, want %q", got, tusVersion)
+			}
+		})
+	}
+}
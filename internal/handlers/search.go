@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"qb/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Search handles full-text search over courses and the questions the user can see
+func Search(c *gin.Context) {
+	q := c.Query("q")
+	userID, role := optionalUser(c)
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	results, err := services.Search(q, userID, role, page, limit)
+	Res.Send(c, results, err)
+}
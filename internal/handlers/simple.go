@@ -96,8 +96,10 @@ func GetSessions(c *gin.Context) {
 	Res.Send(c, sessions, err)
 }
 
+// DeleteSession also removes the session's questions and their images
 func DeleteSession(c *gin.Context) {
-	deleteResourceByStringID(c, &models.Session{}, "Session")
+	err := services.DeleteSession(c.Param("id"))
+	Res.Send(c, nil, err, "Session deleted successfully")
 }
 
 // Request handlers
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"qb/internal/services"
+	"qb/pkg/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSolutions handles listing the solutions of a question
+func GetSolutions(c *gin.Context) {
+	userID, role := optionalUser(c)
+
+	solutions, err := services.GetSolutions(c.Param("id"), userID, role)
+	Res.Send(c, solutions, err)
+}
+
+// CreateSolution handles submitting a worked solution for a question
+func CreateSolution(c *gin.Context) {
+	var input models.CreateSolutionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	solution, err := services.CreateSolution(c.Param("id"), userID, role, input)
+	Res.Created(c, solution, err)
+}
+
+// DeleteSolution handles removing a solution and its pages
+func DeleteSolution(c *gin.Context) {
+	userID, role, err := currentUser(c)
+	if err != nil {
+		Res.Send(c, nil, err)
+		return
+	}
+
+	err = services.DeleteSolution(c.Param("id"), c.Param("solutionId"), userID, role)
+	Res.Send(c, nil, err, "Solution deleted successfully")
+}
+
+// GetPendingSolutions handles listing the solution moderation queue
+func GetPendingSolutions(c *gin.Context) {
+	page, limit := services.GetPageQuery(c.Query("page"), c.Query("limit"))
+
+	solutions, err := services.GetPendingSolutions(page, limit)
+	Res.Send(c, solutions, err)
+}
+
+// ApproveSolution handles approving a pending solution
+func ApproveSolution(c *gin.Context) {
+	var input models.ModerateQuestionDTO
+	if err := bindOptionalJSON(c, &input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	solution, err := services.ApproveSolution(c.Param("id"), input)
+	Res.Send(c, solution, err, "Solution approved successfully")
+}
+
+// RejectSolution handles rejecting a solution with a reason
+func RejectSolution(c *gin.Context) {
+	var input models.ModerateQuestionDTO
+	if err := c.ShouldBindJSON(&input); err != nil {
+		Res.Invalid(c, err)
+		return
+	}
+
+	solution, err := services.RejectSolution(c.Param("id"), input)
+	Res.Send(c, solution, err, "Solution rejected successfully")
+}
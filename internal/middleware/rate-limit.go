@@ -26,6 +26,29 @@ func RateLimitMiddleware(limiter *services.RateLimitService) gin.HandlerFunc {
 	}
 }
 
+// UserRateLimitMiddleware rate limits per signed-in user, falling back to the client IP.
+// It must run after JWTAuthMiddleware so the user ID is available.
+func UserRateLimitMiddleware(limiter *services.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.ClientIP()
+		if userID := c.GetString("userID"); userID != "" {
+			key = "user:" + userID
+		}
+		
+		if !limiter.IsAllowed(key) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"error":   "Rate limit exceeded. Please try again later.",
+				"code":    "RATE_LIMIT_EXCEEDED",
+			})
+			c.Abort()
+			return
+		}
+		
+		c.Next()
+	}
+}
+
 // UploadRateLimit creates middleware for upload rate limiting
 func UploadRateLimit() gin.HandlerFunc {
 	return RateLimitMiddleware(services.GetUploadRateLimiter())
@@ -34,4 +57,9 @@ func UploadRateLimit() gin.HandlerFunc {
 // GeneralRateLimit creates middleware for general rate limiting
 func GeneralRateLimit() gin.HandlerFunc {
 	return RateLimitMiddleware(services.GetGeneralRateLimiter())
+}
+
+// ReportRateLimit creates middleware for per-user report rate limiting
+func ReportRateLimit() gin.HandlerFunc {
+	return UserRateLimitMiddleware(services.GetReportRateLimiter())
 } 
@@ -3,14 +3,23 @@ package routes
 import (
 	"qb/internal/handlers"
 	"qb/internal/middleware"
+	"qb/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(router *gin.Engine) {
+	// Uploaded pages, when they are kept on local disk or in a private bucket
+	if dir, ok := services.LocalStaticDir(); ok {
+		router.Static(services.FilesRoute, dir)
+	} else if services.ServesPresignedFiles() {
+		router.GET(services.FilesRoute+"/*key", handlers.RedirectToFile)
+	}
+
 	v1 := router.Group("/api/v1")
 	{
 		v1.GET("/", handlers.Status)
+		v1.GET("/search", handlers.Auth.OptionalAuth(), handlers.Search) // Public read, uploaders and admins see more
 		
 		// Auth routes (public)
 		auth := v1.Group("/auth")
@@ -42,7 +51,7 @@ func SetupRoutes(router *gin.Engine) {
 		{
 			session.GET("", handlers.GetSessions) // Public read
 			session.POST("", handlers.Auth.JWTAuthMiddleware(), handlers.CreateSession) // Protected
-			session.DELETE("/:id", handlers.Auth.JWTAuthMiddleware(), handlers.DeleteSession) // Protected
+			session.DELETE("/:id", handlers.Auth.JWTAuthMiddleware(), handlers.Auth.RequireAdmin(), handlers.DeleteSession) // Admin only, deletes the session's questions
 		}
 
 		// Course routes
@@ -51,9 +60,12 @@ func SetupRoutes(router *gin.Engine) {
 			course.GET("", handlers.GetAllCourses) // Public read
 			course.GET("/:dept/:level/:semester", handlers.FilterCourses) // Public read
 			course.POST("", handlers.Auth.JWTAuthMiddleware(), handlers.CreateCourse) // Protected
-			course.DELETE("/:id", handlers.Auth.JWTAuthMiddleware(), handlers.DeleteCourse) // Protected
+			course.DELETE("/:id", handlers.Auth.JWTAuthMiddleware(), handlers.Auth.RequireAdmin(), handlers.DeleteCourse) // Admin only, deletes the course's questions
 		}
 
+		// Archive routes, outside /course so the course ID gets its own parameter
+		v1.GET("/archive/course/:id", handlers.Auth.OptionalAuth(), handlers.GetCourseArchive) // Public read
+
 		// Department routes
 		department := v1.Group("/department")
 		{
@@ -65,9 +77,68 @@ func SetupRoutes(router *gin.Engine) {
 		// Question routes
 		question := v1.Group("/question")
 		{
-			question.GET("", handlers.GetQuestions) // Public read
-			question.GET("/:id", handlers.GetQuestionByID) // Public read
+			question.GET("", handlers.Auth.OptionalAuth(), handlers.GetQuestions) // Public read, uploaders and admins see more
+			question.GET("/trending", handlers.Auth.OptionalAuth(), handlers.GetTrendingQuestions) // Public read, uploaders and admins see more
+			question.GET("/mine", handlers.Auth.JWTAuthMiddleware(), handlers.GetMyQuestions) // Protected
+			question.GET("/:id", handlers.Auth.OptionalAuth(), handlers.GetQuestionByID) // Public read, uploaders and admins see more
+			question.GET("/:id/download", handlers.Auth.OptionalAuth(), handlers.DownloadQuestion) // Public read, uploaders and admins see more
+			question.GET("/:id/print", handlers.Auth.OptionalAuth(), handlers.PrintQuestion) // Public read, uploaders and admins see more
+			question.GET("/:id/history", handlers.Auth.JWTAuthMiddleware(), handlers.GetModerationHistory) // Protected, uploader or admin
 			question.POST("", handlers.Auth.JWTAuthMiddleware(), handlers.CreateQuestion) // Protected
+			question.PATCH("/:id", handlers.Auth.JWTAuthMiddleware(), handlers.UpdateQuestion) // Protected, uploader or admin
+			question.DELETE("/:id", handlers.Auth.JWTAuthMiddleware(), handlers.DeleteQuestion) // Protected, uploader or admin
+			question.GET("/:id/solutions", handlers.Auth.OptionalAuth(), handlers.GetSolutions) // Public read, authors and admins see more
+			question.POST("/:id/solutions", handlers.Auth.JWTAuthMiddleware(), handlers.CreateSolution) // Protected
+			question.DELETE("/:id/solutions/:solutionId", handlers.Auth.JWTAuthMiddleware(), handlers.DeleteSolution) // Protected, author or admin
+			question.GET("/:id/comments", handlers.Auth.OptionalAuth(), handlers.GetComments) // Public read
+			question.GET("/:id/comments/:commentId/replies", handlers.Auth.OptionalAuth(), handlers.GetCommentReplies) // Public read
+			question.POST("/:id/comments", handlers.Auth.JWTAuthMiddleware(), handlers.CreateComment) // Protected
+			question.PATCH("/:id/comments/:commentId", handlers.Auth.JWTAuthMiddleware(), handlers.UpdateComment) // Protected, author only
+			question.DELETE("/:id/comments/:commentId", handlers.Auth.JWTAuthMiddleware(), handlers.DeleteComment) // Protected, author or admin
+			question.POST("/:id/report", handlers.Auth.JWTAuthMiddleware(), middleware.ReportRateLimit(), handlers.ReportQuestion) // Protected, rate limited per user
+			question.PUT("/:id/rating", handlers.Auth.JWTAuthMiddleware(), handlers.RateQuestion) // Protected
+			question.DELETE("/:id/rating", handlers.Auth.JWTAuthMiddleware(), handlers.RemoveRating) // Protected
+			question.PUT("/:id/bookmark", handlers.Auth.JWTAuthMiddleware(), handlers.BookmarkQuestion) // Protected
+			question.DELETE("/:id/bookmark", handlers.Auth.JWTAuthMiddleware(), handlers.RemoveBookmark) // Protected
+			question.PUT("/:id/images/order", handlers.Auth.JWTAuthMiddleware(), handlers.ReorderQuestionImages) // Protected, uploader or admin
+			question.PUT("/:id/images/:imageId", handlers.Auth.JWTAuthMiddleware(), handlers.ReplaceQuestionImage) // Protected, uploader or admin
+			question.DELETE("/:id/images/:imageId", handlers.Auth.JWTAuthMiddleware(), handlers.RemoveQuestionImage) // Protected, uploader or admin
+		}
+
+		// Current user's saved questions
+		me := v1.Group("/me", handlers.Auth.JWTAuthMiddleware())
+		{
+			me.GET("/bookmarks", handlers.GetMyBookmarks)
+			me.GET("/collections", handlers.GetMyCollections)
+			me.POST("/collections", handlers.CreateCollection)
+			me.GET("/collections/:id", handlers.GetCollection)
+			me.PATCH("/collections/:id", handlers.UpdateCollection)
+			me.DELETE("/collections/:id", handlers.DeleteCollection)
+			me.POST("/collections/:id/items", handlers.AddCollectionItem)
+			me.PUT("/collections/:id/items/order", handlers.ReorderCollection)
+			me.DELETE("/collections/:id/items/:questionId", handlers.RemoveCollectionItem)
+		}
+
+		// Shared collections
+		v1.GET("/collection/:id", handlers.Auth.OptionalAuth(), handlers.GetCollection) // Public read while shared, owner and admins always
+
+		// Admin routes
+		admin := v1.Group("/admin", handlers.Auth.JWTAuthMiddleware(), handlers.Auth.RequireAdmin())
+		{
+			adminQuestions := admin.Group("/questions")
+			adminQuestions.GET("", handlers.GetPendingQuestions)
+			adminQuestions.POST("/:id/approve", handlers.ApproveQuestion)
+			adminQuestions.POST("/:id/reject", handlers.RejectQuestion)
+
+			adminSolutions := admin.Group("/solutions")
+			adminSolutions.GET("", handlers.GetPendingSolutions)
+			adminSolutions.POST("/:id/approve", handlers.ApproveSolution)
+			adminSolutions.POST("/:id/reject", handlers.RejectSolution)
+
+			adminReports := admin.Group("/reports")
+			adminReports.GET("", handlers.GetReports)
+			adminReports.POST("/:id/resolve", handlers.ResolveReport)
+			adminReports.POST("/:id/dismiss", handlers.DismissReport)
 		}
 
 		// Request routes
@@ -82,5 +153,16 @@ func SetupRoutes(router *gin.Engine) {
 			handlers.Auth.JWTAuthMiddleware(), 
 			handlers.UploadImages,
 		) // Protected
+
+		// Resumable (tus 1.0.0) uploads; finished files are staged like /upload-images
+		v1.OPTIONS("/uploads", handlers.TusHeaders(), handlers.TusOptions) // Public, protocol discovery
+		uploads := v1.Group("/uploads", handlers.Auth.JWTAuthMiddleware(), handlers.TusHeaders())
+		{
+			uploads.POST("", middleware.UploadRateLimit(), handlers.CreateResumableUpload) // Protected
+			uploads.HEAD("/:id", handlers.GetResumableUploadOffset) // Protected, uploader only
+			uploads.PATCH("/:id", handlers.AppendResumableUpload) // Protected, uploader only
+			uploads.DELETE("/:id", handlers.DeleteResumableUpload) // Protected, uploader only
+		}
+		v1.GET("/uploads/requests/:requestId", handlers.Auth.JWTAuthMiddleware(), handlers.GetResumableRequest) // Protected, uploader only
 	}
 }
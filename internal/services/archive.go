@@ -0,0 +1,103 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"qb/pkg/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ArchiveManifest describes the contents of a course archive; it is written as manifest.json
+type ArchiveManifest struct {
+	CourseID    string                    `json:"courseId"`
+	CourseTitle string                    `json:"courseTitle"`
+	GeneratedAt time.Time                 `json:"generatedAt"`
+	Questions   []ArchiveManifestQuestion `json:"questions"`
+}
+
+// ArchiveManifestQuestion lists a question's metadata and the archive paths of its pages
+type ArchiveManifestQuestion struct {
+	ID          string              `json:"id"`
+	SessionID   string              `json:"sessionId"`
+	Type        models.QuestionType `json:"type"`
+	Paper       int                 `json:"paper"`
+	PaperLabel  *string             `json:"paperLabel,omitempty"`
+	Lecturer    *string             `json:"lecturer,omitempty"`
+	TimeAllowed *int                `json:"timeAllowed,omitempty"`
+	DocLink     *string             `json:"docLink,omitempty"`
+	Tips        *string             `json:"tips,omitempty"`
+	Pages       []string            `json:"pages"`
+}
+
+// GetArchivableCourse loads a course with its approved questions and their pages
+func GetArchivableCourse(id string) (*models.Course, error) {
+	var course models.Course
+	if err := db.Preload("Questions", func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("approved = ?", true).Order("session_id ASC, type ASC, paper ASC, id ASC")
+	}).Preload("Questions.Images", orderedImages).
+		Where("id = ?", id).First(&course).Error; err != nil {
+		return nil, errS.Db(err, "Course")
+	}
+
+	if len(course.Questions) == 0 {
+		return nil, &models.BusinessError{Code: 404, Message: "Course has no approved questions to archive"}
+	}
+
+	return &course, nil
+}
+
+// WriteCourseArchive streams every approved question of a course into a ZIP laid out as
+// <session>/<type>/paper-<paper>/page-N.<ext>, followed by manifest.json. Pages are fetched one at a time.
+func WriteCourseArchive(w io.Writer, course *models.Course) error {
+	archive := zip.NewWriter(w)
+
+	manifest := ArchiveManifest{
+		CourseID:    course.ID,
+		CourseTitle: course.Title,
+		GeneratedAt: time.Now(),
+		Questions:   make([]ArchiveManifestQuestion, 0, len(course.Questions)),
+	}
+
+	for _, question := range course.Questions {
+		folder := path.Join(question.SessionID, string(question.Type), fmt.Sprintf("paper-%d", question.Paper))
+		entry := ArchiveManifestQuestion{
+			ID:          question.ID,
+			SessionID:   question.SessionID,
+			Type:        question.Type,
+			Paper:       question.Paper,
+			PaperLabel:  question.PaperLabel,
+			Lecturer:    question.Lecturer,
+			TimeAllowed: question.TimeAllowed,
+			DocLink:     question.DocLink,
+			Tips:        question.Tips,
+			Pages:       make([]string, 0, len(question.Images)),
+		}
+
+		for _, image := range question.Images {
+			name, err := writePageToZip(archive, &image, path.Join(folder, fmt.Sprintf("page-%d", image.Page)))
+			if err != nil {
+				return err
+			}
+			entry.Pages = append(entry.Pages, name)
+		}
+
+		manifest.Questions = append(manifest.Questions, entry)
+	}
+
+	manifestEntry, err := archive.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(manifestEntry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"qb/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookmarkQuestion saves a question the user can see to their bookmarks.
+// Bookmarking twice is a no-op; only the first bookmark counts as engagement.
+func BookmarkQuestion(questionID, userID, role string) (*models.Bookmark, error) {
+	visibleID, err := getVisibleQuestionID(questionID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmark := models.Bookmark{UserID: userID, QuestionID: visibleID}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&bookmark)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return recordEngagement(tx, []models.EngagementEvent{newEngagementEvent(visibleID, userID, models.EngagementTypeBookmark)})
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &bookmark, nil
+}
+
+// RemoveBookmark removes a question from the user's bookmarks; removing a missing bookmark is a no-op
+func RemoveBookmark(questionID, userID string) error {
+	if err := db.Where("user_id = ? AND question_id = ?", userID, questionID).Delete(&models.Bookmark{}).Error; err != nil {
+		return errS.Db(err)
+	}
+	return nil
+}
+
+// GetBookmarks lists the user's bookmarked questions, most recently saved first.
+// Questions the user can no longer see (e.g. rejected after bookmarking) are left out.
+func GetBookmarks(userID, role string, page, limit int) ([]models.Bookmark, *models.PaginationMeta, error) {
+	page, limit = clampPage(page, limit)
+
+	query := db.Model(&models.Bookmark{}).
+		Joins("JOIN questions ON questions.id = bookmarks.question_id").
+		Scopes(visibleTo(userID, role)).
+		Where("bookmarks.user_id = ?", userID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	offset := (page - 1) * limit
+
+	var bookmarks []models.Bookmark
+	if err := query.Select("bookmarks.*").
+		Preload("Question.Course").Preload("Question.Session").Preload("Question.Images", orderedImages).
+		Order("bookmarks.created_at DESC").Offset(offset).Limit(limit).Find(&bookmarks).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	meta := &models.PaginationMeta{
+		Total:   &total,
+		Page:    page,
+		Limit:   limit,
+		HasNext: int64(offset+len(bookmarks)) < total,
+	}
+
+	return bookmarks, meta, nil
+}
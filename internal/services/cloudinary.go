@@ -3,164 +3,222 @@ package services
 import (
 	"context"
 	"fmt"
-	"mime/multipart"
-	"net/http"
+	"io"
+	"log"
 	"qb/pkg/models"
 	"strings"
 	"time"
 
+	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 )
 
-// UploadFileToTemp uploads a single image file to temporary folder with request-based tagging
-func UploadFileToTemp(fileHeader *multipart.FileHeader, requestID string) (string, string, error) {
-	if cldS == nil {
-		return "", "", models.ErrInternal
-	}
+// cloudinaryStore keeps pages on Cloudinary. Besides living under their folder, permanent assets carry
+// a question_<id> or solution_<id> tag so they can still be found if a folder move half fails.
+type cloudinaryStore struct {
+	cld *cloudinary.Cloudinary
+}
 
-	file, err := fileHeader.Open()
+// NewCloudinaryStore creates a Cloudinary-backed store from a cloudinary:// URL
+func NewCloudinaryStore(cloudinaryURL string) (ObjectStore, error) {
+	cld, err := cloudinary.NewFromURL(cloudinaryURL)
 	if err != nil {
-		return "", "", errS.Invalid(fmt.Sprintf("Failed to open file: %v", err))
+		return nil, err
 	}
-	defer file.Close()
+	return &cloudinaryStore{cld: cld}, nil
+}
 
+// UploadTemp uploads an image to the temporary folder, tagged with its request and expiry for auto-cleanup
+func (s *cloudinaryStore) UploadTemp(ctx context.Context, file io.Reader, requestID string) (string, error) {
 	// Generate expiration timestamp (24 hours from now)
-	expirationTime := time.Now().Add(24 * time.Hour).Unix()
+	expirationTime := time.Now().Add(tempUploadTTL).Unix()
 
-	// Upload parameters with tagging for auto-cleanup
 	uploadParams := uploader.UploadParams{
-		Folder: "qb_temp_uploads",
+		Folder: tempFolder,
 		Tags: []string{
 			"temp_upload",
 			fmt.Sprintf("req_%s", requestID),
 			fmt.Sprintf("expires_%d", expirationTime),
 		},
-		ResourceType: "image",
+		ResourceType:   "image",
 		Transformation: "f_auto,q_auto", // Auto-detect format and optimize quality
 	}
 
-	ctx := context.Background()
-	result, err := cldS.Upload.Upload(ctx, file, uploadParams)
+	result, err := s.cld.Upload.Upload(ctx, file, uploadParams)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to upload to Cloudinary: %w", err)
+		return "", fmt.Errorf("failed to upload to Cloudinary: %w", err)
 	}
-
-	return result.SecureURL, result.PublicID, nil
-}
-
-// MoveFileToPermanent moves image from temp folder to permanent folder
-func MoveFileToPermanent(tempPublicID, questionID string) (string, error) {
-	if cldS == nil {
-		return "", models.ErrInternal
+	if result.Error.Message != "" {
+		return "", fmt.Errorf("failed to upload to Cloudinary: %s", result.Error.Message)
 	}
 
-	ctx := context.Background()
-
-	// Generate new public ID for permanent location
-	newPublicID := fmt.Sprintf("qb_questions/%s/%s", questionID, extractFilenameFromPublicID(tempPublicID))
+	return result.PublicID, nil
+}
 
-	// Copy the file to permanent location with new upload
+// Promote re-uploads a temp image into the permanent folder, tags it and deletes the temp copy
+func (s *cloudinaryStore) Promote(ctx context.Context, tempKey, folder string) (*models.ImageAsset, error) {
 	uploadParams := uploader.UploadParams{
-		PublicID: newPublicID,
+		PublicID: folder + extractFilenameFromPublicID(tempKey),
 		Tags: []string{
 			"permanent",
-			fmt.Sprintf("question_%s", questionID),
+			folderTag(folder),
 		},
 		Transformation: "f_auto,q_auto",
-		ResourceType: "image",
+		ResourceType:   "image",
 	}
 
 	// Get the temporary file URL
-	tempAsset, err := cldS.Image(tempPublicID)
+	tempURL, err := s.URL(tempKey)
 	if err != nil {
-		return "", fmt.Errorf("failed to get temp image asset: %w", err)
+		return nil, fmt.Errorf("failed to generate temp image URL: %w", err)
 	}
-	tempURL, err := tempAsset.String()
+
+	result, err := s.cld.Upload.Upload(ctx, tempURL, uploadParams)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate temp image URL: %w", err)
+		return nil, fmt.Errorf("failed to move file to permanent location: %w", err)
 	}
-	
-	result, err := cldS.Upload.Upload(ctx, tempURL, uploadParams)
-	if err != nil {
-		return "", fmt.Errorf("failed to move file to permanent location: %w", err)
+	if result.Error.Message != "" {
+		return nil, fmt.Errorf("failed to move file to permanent location: %s", result.Error.Message)
 	}
 
 	// Delete the temporary file
-	_, err = cldS.Upload.Destroy(ctx, uploader.DestroyParams{
-		PublicID: tempPublicID,
+	if err := s.Delete(ctx, tempKey); err != nil {
+		// Log error but don't fail the operation since the file was moved successfully
+		log.Printf("Warning: Failed to delete temp file %s: %v", tempKey, err)
+	}
+
+	return &models.ImageAsset{
+		PublicID: result.PublicID,
+		URL:      result.SecureURL,
+		Width:    result.Width,
+		Height:   result.Height,
+		Bytes:    result.Bytes,
+		Format:   result.Format,
+	}, nil
+}
+
+// Move renames an asset and swaps its folder tag for the new folder's
+func (s *cloudinaryStore) Move(ctx context.Context, fromKey, toKey string) (string, error) {
+	result, err := s.cld.Upload.Rename(ctx, uploader.RenameParams{
+		FromPublicID: fromKey,
+		ToPublicID:   toKey,
 	})
 	if err != nil {
-		// Log error but don't fail the operation since the file was moved successfully
-		fmt.Printf("Warning: Failed to delete temp file %s: %v\n", tempPublicID, err)
+		return "", err
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("%v", result.Error)
+	}
+
+	// Tags only drive cleanup, so a failure here is logged rather than undoing the move
+	fromTag, toTag := folderTag(folderOf(fromKey)), folderTag(folderOf(toKey))
+	if fromTag != toTag {
+		if _, err := s.cld.Upload.RemoveTag(ctx, uploader.RemoveTagParams{Tag: fromTag, PublicIDs: []string{result.PublicID}}); err != nil {
+			log.Printf("Warning: Failed to remove tag %s: %v", fromTag, err)
+		}
+		if _, err := s.cld.Upload.AddTag(ctx, uploader.AddTagParams{Tag: toTag, PublicIDs: []string{result.PublicID}}); err != nil {
+			log.Printf("Warning: Failed to add tag %s: %v", toTag, err)
+		}
 	}
 
 	return result.SecureURL, nil
 }
 
-// BuildCloudinaryURL constructs a Cloudinary URL from a public ID
-func BuildCloudinaryURL(publicID string) (string, error) {
-	if cldS == nil {
-		return "", models.ErrInternal
-	}
-	
-	asset, err := cldS.Image(publicID)
+// Delete destroys a single asset
+func (s *cloudinaryStore) Delete(ctx context.Context, key string) error {
+	result, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID: key,
+	})
 	if err != nil {
-		return "", errS.Invalid(fmt.Sprintf("Failed to create image asset: %v", err))
+		return fmt.Errorf("failed to destroy %s: %w", key, err)
 	}
-	
-	url, err := asset.String()
-	if err != nil {
-		return "", errS.Invalid(fmt.Sprintf("Failed to generate image URL: %v", err))
+	if result.Error.Message != "" {
+		return fmt.Errorf("failed to destroy %s: %s", key, result.Error.Message)
 	}
-	
-	return url, nil
+	return nil
 }
 
-// ValidateImageFile validates uploaded image files
-func ValidateImageFile(fileHeader *multipart.FileHeader) error {
-	// Check file size (10MB limit)
-	if fileHeader.Size > 10*1024*1024 {
-		return errS.Invalid("File size exceeds 10MB limit")
+// DeletePrefix destroys every asset carrying the folder's tag, then everything left under the prefix
+func (s *cloudinaryStore) DeletePrefix(ctx context.Context, prefix string) error {
+	// The Admin API deletes in batches and reports Partial until everything matching is gone
+	if tag := folderTag(prefix); tag != "" {
+		for cursor := ""; ; {
+			result, err := s.cld.Admin.DeleteAssetsByTag(ctx, admin.DeleteAssetsByTagParams{
+				Tag:        tag,
+				NextCursor: cursor,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete assets tagged %s: %w", tag, err)
+			}
+			if result.Error.Message != "" {
+				return fmt.Errorf("failed to delete assets tagged %s: %s", tag, result.Error.Message)
+			}
+			if !result.Partial {
+				break
+			}
+			cursor = result.NextCursor
+		}
+	}
+
+	for cursor := ""; ; {
+		result, err := s.cld.Admin.DeleteAssetsByPrefix(ctx, admin.DeleteAssetsByPrefixParams{
+			Prefix:     api.CldAPIArray{prefix},
+			NextCursor: cursor,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete assets under %s: %w", prefix, err)
+		}
+		if result.Error.Message != "" {
+			return fmt.Errorf("failed to delete assets under %s: %s", prefix, result.Error.Message)
+		}
+		if !result.Partial {
+			break
+		}
+		cursor = result.NextCursor
 	}
-	
-	// Check MIME type
-	file, err := fileHeader.Open()
+
+	return nil
+}
+
+// URL constructs the delivery URL of an asset
+func (s *cloudinaryStore) URL(key string) (string, error) {
+	asset, err := s.cld.Image(key)
 	if err != nil {
-		return errS.Invalid(fmt.Sprintf("Failed to open file: %v", err))
+		return "", fmt.Errorf("failed to create image asset: %w", err)
 	}
-	defer file.Close()
-	
-	// Read first 512 bytes to detect content type
-	buffer := make([]byte, 512)
-	_, err = file.Read(buffer)
+	return asset.String()
+}
+
+// JPEGURL constructs a delivery URL that converts the asset to JPEG
+func (s *cloudinaryStore) JPEGURL(key string) (string, error) {
+	asset, err := s.cld.Image(key)
 	if err != nil {
-		return errS.Invalid(fmt.Sprintf("Failed to read file content: %v", err))
+		return "", fmt.Errorf("failed to create image asset: %w", err)
 	}
-	
-	contentType := http.DetectContentType(buffer)
-	allowedTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/jpg":  true,
-		"image/png":  true,
-		"image/webp": true,
+	asset.Transformation = "f_jpg"
+
+	return asset.String()
+}
+
+// folderTag returns the tag carried by every asset in a permanent question or solution folder
+func folderTag(folder string) string {
+	if id, ok := strings.CutPrefix(folder, questionsRoot); ok {
+		return questionTag(strings.TrimSuffix(id, "/"))
 	}
-	
-	if !allowedTypes[contentType] {
-		return errS.Invalid(fmt.Sprintf("Unsupported file type: %s. Allowed types: JPEG, PNG, WebP", contentType))
+	if id, ok := strings.CutPrefix(folder, solutionsRoot); ok {
+		return solutionTag(strings.TrimSuffix(id, "/"))
 	}
-	
-	return nil
+	return ""
 }
 
-// extractFilenameFromPublicID extracts the filename part from a Cloudinary public ID
-func extractFilenameFromPublicID(publicID string) string {
-	parts := strings.Split(publicID, "/")
-	return parts[len(parts)-1]
+// questionTag returns the tag attached to every permanent page of a question
+func questionTag(questionID string) string {
+	return fmt.Sprintf("question_%s", questionID)
 }
 
-// DetectContentType mimics http.DetectContentType but can be overridden for testing
-var DetectContentType = func(data []byte) string {
-	// This would normally be http.DetectContentType(data)
-	// But we'll keep it simple for now and infer from file extension
-	return "image/jpeg" // Default for now, can be enhanced
-} 
+// solutionTag returns the tag attached to every permanent page of a solution
+func solutionTag(solutionID string) string {
+	return fmt.Sprintf("solution_%s", solutionID)
+}
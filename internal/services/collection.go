@@ -0,0 +1,275 @@
+package services
+
+import (
+	"qb/pkg/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// itemCountColumn counts a collection's items alongside the collection itself
+const itemCountColumn = "(SELECT COUNT(*) FROM collection_items WHERE collection_items.collection_id = collections.id) AS item_count"
+
+// GetMyCollections lists the user's collections, most recently changed first, with their item counts
+func GetMyCollections(userID string, page, limit int) ([]models.Collection, *models.PaginationMeta, error) {
+	page, limit = clampPage(page, limit)
+
+	query := db.Model(&models.Collection{}).Where("owner_id = ?", userID).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	offset := (page - 1) * limit
+
+	var collections []models.Collection
+	if err := query.Select("collections.*, " + itemCountColumn).
+		Order("updated_at DESC, id ASC").Offset(offset).Limit(limit).Find(&collections).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	meta := &models.PaginationMeta{
+		Total:   &total,
+		Page:    page,
+		Limit:   limit,
+		HasNext: int64(offset+len(collections)) < total,
+	}
+
+	return collections, meta, nil
+}
+
+// CreateCollection creates an empty collection for the user
+func CreateCollection(userID string, input models.CreateCollectionDTO) (*models.Collection, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errS.Invalid("Collection name cannot be empty")
+	}
+
+	collection := models.Collection{
+		ID:          uuid.New().String(),
+		OwnerID:     userID,
+		Name:        name,
+		Description: input.Description,
+		Shared:      input.Shared,
+	}
+	if err := db.Create(&collection).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &collection, nil
+}
+
+// GetCollection returns a collection with its questions in order.
+// Owners and admins can always read it; anyone else only while it is shared.
+// Questions the viewer can't see are left out of the items.
+func GetCollection(id, userID, role string) (*models.Collection, error) {
+	var collection models.Collection
+	if err := db.Preload("Owner", publicAuthor).Where("id = ?", id).First(&collection).Error; err != nil {
+		return nil, errS.Db(err, "Collection")
+	}
+
+	if !collection.Shared && collection.OwnerID != userID && role != string(models.RoleAdmin) {
+		// Private collections look the same as missing ones to everyone else
+		return nil, &models.BusinessError{Code: 404, Message: "Collection not found"}
+	}
+
+	var items []models.CollectionItem
+	if err := db.Joins("JOIN questions ON questions.id = collection_items.question_id").
+		Scopes(visibleTo(userID, role)).
+		Select("collection_items.*").
+		Preload("Question.Course").Preload("Question.Session").Preload("Question.Images", orderedImages).
+		Where("collection_items.collection_id = ?", collection.ID).
+		Order("collection_items.position ASC").Find(&items).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+	collection.Items = items
+	collection.ItemCount = len(items)
+
+	return &collection, nil
+}
+
+// UpdateCollection renames, describes or shares/unshares the user's collection
+func UpdateCollection(id, userID string, input models.UpdateCollectionDTO) (*models.Collection, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	collection, err := getOwnedCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, errS.Invalid("Collection name cannot be empty")
+		}
+		updates["name"] = name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.Shared != nil {
+		updates["shared"] = *input.Shared
+	}
+	if len(updates) == 0 {
+		return collection, nil
+	}
+
+	if err := db.Model(collection).Updates(updates).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return GetCollection(collection.ID, userID, "")
+}
+
+// DeleteCollection deletes the user's collection; the questions themselves are untouched
+func DeleteCollection(id, userID string) error {
+	collection, err := getOwnedCollection(id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Delete(collection).Error; err != nil {
+		return errS.Db(err)
+	}
+	return nil
+}
+
+// AddCollectionItem appends a question the user can see to the end of their collection
+func AddCollectionItem(id, userID, role string, input models.AddCollectionItemDTO) (*models.Collection, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	collection, err := getOwnedCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionID, err := getVisibleQuestionID(input.QuestionID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&models.CollectionItem{}).Where("collection_id = ?", collection.ID).
+			Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+
+		item := models.CollectionItem{CollectionID: collection.ID, QuestionID: questionID, Position: last + 1}
+		if err := tx.Create(&item).Error; err != nil {
+			return err
+		}
+		// Touch the collection so it sorts as recently changed
+		return tx.Model(collection).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		dbErr := errS.Db(err)
+		if dbErr == models.ErrDuplicate {
+			return nil, &models.BusinessError{Code: 409, Message: "Question is already in this collection"}
+		}
+		return nil, dbErr
+	}
+
+	return GetCollection(collection.ID, userID, role)
+}
+
+// RemoveCollectionItem takes a question out of the user's collection and closes the gap it leaves
+func RemoveCollectionItem(id, userID, role, questionID string) (*models.Collection, error) {
+	collection, err := getOwnedCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item models.CollectionItem
+	if err := db.Where("collection_id = ? AND question_id = ?", collection.ID, questionID).First(&item).Error; err != nil {
+		return nil, errS.Db(err, "Collection item")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ? AND question_id = ?", collection.ID, questionID).
+			Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.CollectionItem{}).
+			Where("collection_id = ? AND position > ?", collection.ID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return GetCollection(collection.ID, userID, role)
+}
+
+// ReorderCollection sets a new order for the user's collection; questionIds must list every item exactly once
+func ReorderCollection(id, userID, role string, input models.ReorderCollectionDTO) (*models.Collection, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	collection, err := getOwnedCollection(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var current []string
+	if err := db.Model(&models.CollectionItem{}).Where("collection_id = ?", collection.ID).
+		Pluck("question_id", &current).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	remaining := make(map[string]bool, len(current))
+	for _, questionID := range current {
+		remaining[questionID] = true
+	}
+	for _, questionID := range input.QuestionIDs {
+		if !remaining[questionID] {
+			return nil, errS.Invalid("questionIds must contain exactly the collection's current questions")
+		}
+		delete(remaining, questionID)
+	}
+	if len(remaining) > 0 {
+		return nil, errS.Invalid("questionIds must contain exactly the collection's current questions")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i, questionID := range input.QuestionIDs {
+			if err := tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND question_id = ?", collection.ID, questionID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(collection).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return GetCollection(collection.ID, userID, role)
+}
+
+// getOwnedCollection loads a collection the user owns
+func getOwnedCollection(id, userID string) (*models.Collection, error) {
+	var collection models.Collection
+	if err := db.Where("id = ?", id).First(&collection).Error; err != nil {
+		return nil, errS.Db(err, "Collection")
+	}
+	// Other users' collections look missing rather than forbidden, like private ones do in GetCollection
+	if collection.OwnerID != userID {
+		return nil, &models.BusinessError{Code: 404, Message: "Collection not found"}
+	}
+	return &collection, nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"qb/pkg/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// replyCountColumn counts a comment's replies alongside the comment itself
+const replyCountColumn = "(SELECT COUNT(*) FROM comments AS replies WHERE replies.parent_id = comments.id) AS reply_count"
+
+// GetComments lists the top-level comments of a question the user can see, newest first, with their reply counts
+func GetComments(questionID, userID, role string, page, limit int) ([]models.Comment, *models.PaginationMeta, error) {
+	if _, err := getVisibleQuestionID(questionID, userID, role); err != nil {
+		return nil, nil, err
+	}
+
+	query := db.Model(&models.Comment{}).Where("question_id = ? AND parent_id IS NULL", questionID)
+	return listComments(query, "comments.created_at DESC, comments.id DESC", page, limit)
+}
+
+// GetReplies lists the replies in a thread, oldest first so the conversation reads top to bottom
+func GetReplies(questionID string, commentID uint, userID, role string, page, limit int) ([]models.Comment, *models.PaginationMeta, error) {
+	if _, err := getVisibleQuestionID(questionID, userID, role); err != nil {
+		return nil, nil, err
+	}
+
+	parent, err := getComment(questionID, commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := db.Model(&models.Comment{}).Where("parent_id = ?", parent.ID)
+	return listComments(query, "comments.created_at ASC, comments.id ASC", page, limit)
+}
+
+// CreateComment starts a thread on an approved question or, with a parent, replies to one.
+// Replying to a reply adds to the same thread, since threads are one level deep.
+func CreateComment(questionID, userID string, input models.CreateCommentDTO) (*models.Comment, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, errS.Invalid("Comment body cannot be empty")
+	}
+
+	var question models.Question
+	if err := db.Select("id", "approved").Where("id = ?", questionID).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+	if !question.Approved {
+		return nil, &models.BusinessError{Code: 403, Message: "Only approved questions can be commented on"}
+	}
+
+	comment := models.Comment{
+		QuestionID: question.ID,
+		AuthorID:   &userID,
+		Body:       body,
+	}
+
+	if input.ParentID != nil {
+		parent, err := getComment(question.ID, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		threadID := parent.ID
+		if parent.ParentID != nil {
+			threadID = *parent.ParentID
+		}
+		comment.ParentID = &threadID
+	}
+
+	if err := db.Create(&comment).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &comment, nil
+}
+
+// UpdateComment changes the body of the author's own comment
+func UpdateComment(questionID string, commentID uint, userID string, input models.UpdateCommentDTO) (*models.Comment, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return nil, errS.Invalid("Comment body cannot be empty")
+	}
+
+	comment, err := getComment(questionID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.RemovedAt != nil {
+		return nil, &models.BusinessError{Code: 409, Message: "Removed comments cannot be edited"}
+	}
+	if comment.AuthorID == nil || *comment.AuthorID != userID {
+		return nil, models.ErrForbidden
+	}
+
+	now := time.Now()
+	if err := db.Model(comment).Updates(map[string]interface{}{"body": body, "edited_at": now}).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+	comment.Body = body
+	comment.EditedAt = &now
+
+	return comment, nil
+}
+
+// DeleteComment removes a comment; authors may remove their own and admins may remove any.
+// A thread that still has replies keeps an empty placeholder, and the last reply of a removed
+// thread takes the placeholder with it.
+func DeleteComment(questionID string, commentID uint, userID, role string) error {
+	comment, err := getComment(questionID, commentID)
+	if err != nil {
+		return err
+	}
+
+	isAuthor := comment.AuthorID != nil && *comment.AuthorID == userID
+	if !isAuthor && role != string(models.RoleAdmin) {
+		return models.ErrForbidden
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var replies int64
+		if err := tx.Model(&models.Comment{}).Where("parent_id = ?", comment.ID).Count(&replies).Error; err != nil {
+			return err
+		}
+
+		if replies > 0 {
+			return tx.Model(comment).Updates(map[string]interface{}{
+				"body":             "",
+				"author_id":        nil,
+				"removed_at":       time.Now(),
+				"removed_by_admin": !isAuthor,
+			}).Error
+		}
+
+		if err := tx.Delete(comment).Error; err != nil {
+			return err
+		}
+
+		if comment.ParentID == nil {
+			return nil
+		}
+
+		// MySQL can't delete from a table it is also reading in a subquery, so count first
+		var siblings int64
+		if err := tx.Model(&models.Comment{}).Where("parent_id = ?", *comment.ParentID).Count(&siblings).Error; err != nil {
+			return err
+		}
+		if siblings > 0 {
+			return nil
+		}
+		return tx.Where("id = ? AND removed_at IS NOT NULL", *comment.ParentID).Delete(&models.Comment{}).Error
+	})
+	if err != nil {
+		return errS.Db(err)
+	}
+
+	return nil
+}
+
+// listComments returns one page of the query's comments with their authors and reply counts
+func listComments(query *gorm.DB, order string, page, limit int) ([]models.Comment, *models.PaginationMeta, error) {
+	page, limit = clampPage(page, limit)
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	offset := (page - 1) * limit
+
+	var comments []models.Comment
+	if err := query.Select("comments.*, " + replyCountColumn).Preload("Author", publicAuthor).
+		Order(order).Offset(offset).Limit(limit).Find(&comments).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	meta := &models.PaginationMeta{
+		Total:   &total,
+		Page:    page,
+		Limit:   limit,
+		HasNext: int64(offset+len(comments)) < total,
+	}
+
+	return comments, meta, nil
+}
+
+// getComment loads a comment, making sure it belongs to the question in the URL
+func getComment(questionID string, commentID uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := db.Where("id = ? AND question_id = ?", commentID, questionID).First(&comment).Error; err != nil {
+		return nil, errS.Db(err, "Comment")
+	}
+	return &comment, nil
+}
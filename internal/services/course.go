@@ -2,6 +2,8 @@ package services
 
 import (
 	"qb/pkg/models"
+
+	"gorm.io/gorm"
 )
 
 // CreateCourse creates a new course with parsed course code validation
@@ -78,16 +80,28 @@ func FilterCourses(filter models.CourseFilterDTO) ([]models.Course, error) {
 	return courses, nil
 }
 
-// DeleteCourse deletes a course by its ID
+// DeleteCourse deletes a course by its ID along with its questions and their images
 func DeleteCourse(id string) (int64, error) {
-	result := db.Delete(&models.Course{}, "id = ?", id)
-	if result.Error != nil {
-		return 0, result.Error
+	var questionIDs []string
+	if err := db.Model(&models.Question{}).Where("course_id = ?", id).Pluck("id", &questionIDs).Error; err != nil {
+		return 0, errS.Db(err)
 	}
-	
-	if result.RowsAffected == 0 {
-		return 0, models.ErrNotFound
+
+	var rowsAffected int64
+	err := deleteQuestionsWithAssets(questionIDs, func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Course{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+		rowsAffected = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-	
-	return result.RowsAffected, nil
-} 
+
+	return rowsAffected, nil
+}
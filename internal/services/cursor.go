@@ -0,0 +1,74 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strings"
+	"time"
+)
+
+var cursorSecret []byte
+
+// questionCursor marks the last row of a page in (createdAt, id) order
+type questionCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"i"`
+	Order     string    `json:"o"`
+}
+
+var errInvalidCursor = &models.BusinessError{
+	Code:    400,
+	Message: "Invalid cursor",
+	Details: "The cursor is malformed, was tampered with or was issued for a different sort order",
+}
+
+// InitCursorSigner loads the key used to sign pagination cursors, falling back to the JWT secret
+func InitCursorSigner() {
+	secret := utils.GetEnv("CURSOR_SECRET", "")
+	if secret == "" {
+		secret = utils.GetEnvFatal("JWT_SECRET")
+	}
+	cursorSecret = []byte(secret)
+}
+
+// encodeCursor serializes and signs a cursor into an opaque URL-safe token
+func encodeCursor(cursor questionCursor) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signCursor(encoded)
+}
+
+// decodeCursor verifies a cursor token and returns its position
+func decodeCursor(token, order string) (*questionCursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signCursor(encoded))) {
+		return nil, errInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	var cursor questionCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == "" {
+		return nil, errInvalidCursor
+	}
+
+	// A cursor only makes sense in the direction it was issued for
+	if cursor.Order != order {
+		return nil, errInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func signCursor(encoded string) string {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
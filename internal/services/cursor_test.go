@@ -0,0 +1,83 @@
+package services
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursorSecret = []byte("test-secret")
+
+	createdAt := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		cursor questionCursor
+	}{
+		{"newest first", questionCursor{CreatedAt: createdAt, ID: "csc201-2023-t1", Order: "desc"}},
+		{"oldest first", questionCursor{CreatedAt: createdAt, ID: "csc201-2023-e1", Order: "asc"}},
+		{"sub-second time", questionCursor{CreatedAt: createdAt.Add(123456789), ID: "mth101-2022-qz2", Order: "desc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodeCursor(tt.cursor)
+			if strings.ContainsAny(token, "+/=") {
+				t.Fatalf("token %q is not URL-safe", token)
+			}
+
+			decoded, err := decodeCursor(token, tt.cursor.Order)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !decoded.CreatedAt.Equal(tt.cursor.CreatedAt) || decoded.ID != tt.cursor.ID || decoded.Order != tt.cursor.Order {
+				t.Fatalf("decoded %+v, want %+v", *decoded, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	cursorSecret = []byte("test-secret")
+
+	valid := encodeCursor(questionCursor{CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), ID: "csc201-2023-t1", Order: "desc"})
+	encoded, signature, _ := strings.Cut(valid, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2024-01-01T00:00:00Z","i":"other","o":"desc"}`))
+	emptyID := base64.RawURLEncoding.EncodeToString([]byte(`{"t":"2024-01-01T00:00:00Z","i":"","o":"desc"}`))
+	notJSON := base64.RawURLEncoding.EncodeToString([]byte("not json"))
+
+	tests := []struct {
+		name  string
+		token string
+		order string
+	}{
+		{"empty", "", "desc"},
+		{"missing signature", encoded, "desc"},
+		{"tampered payload", forged + "." + signature, "desc"},
+		{"tampered signature", encoded + "." + strings.ToUpper(signature), "desc"},
+		{"other order", valid, "asc"},
+		{"bad base64", "!!!." + signCursor("!!!"), "desc"},
+		{"not json", notJSON + "." + signCursor(notJSON), "desc"},
+		{"empty id", emptyID + "." + signCursor(emptyID), "desc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.token, tt.order); err != errInvalidCursor {
+				t.Fatalf("decodeCursor(%q) error = %v, want errInvalidCursor", tt.token, err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsOtherSecret(t *testing.T) {
+	cursorSecret = []byte("old-secret")
+	token := encodeCursor(questionCursor{CreatedAt: time.Now(), ID: "csc201-2023-t1", Order: "desc"})
+
+	cursorSecret = []byte("new-secret")
+	if _, err := decodeCursor(token, "desc"); err != errInvalidCursor {
+		t.Fatalf("decodeCursor error = %v, want errInvalidCursor", err)
+	}
+}
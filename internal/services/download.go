@@ -0,0 +1,238 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image/jpeg"
+	"io"
+	"log"
+	"net/http"
+	"qb/pkg/models"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+// Supported download formats
+const (
+	DownloadFormatZIP = "zip"
+	DownloadFormatPDF = "pdf"
+)
+
+// pageClient fetches page images from storage; the timeout bounds a single page, not a whole download
+var pageClient = &http.Client{Timeout: 60 * time.Second}
+
+// GetDownloadableQuestion loads a question visible to the user with its course, session and pages in order
+func GetDownloadableQuestion(id, userID, role string) (*models.Question, error) {
+	var question models.Question
+	if err := db.Preload("Course").Preload("Session").Preload("Images", orderedImages).
+		Scopes(visibleTo(userID, role)).Where("questions.id = ?", id).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+
+	if len(question.Images) == 0 {
+		return nil, &models.BusinessError{Code: 404, Message: "Question has no pages to download"}
+	}
+
+	return &question, nil
+}
+
+// IncrementDownloads atomically bumps the download counter of each given question and logs the downloads
+func IncrementDownloads(userID string, ids ...string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	events := make([]models.EngagementEvent, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, newEngagementEvent(id, userID, models.EngagementTypeDownload))
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Question{}).Where("id IN ?", ids).
+			Update("downloads", gorm.Expr("downloads + 1")).Error; err != nil {
+			return err
+		}
+		return recordEngagement(tx, events)
+	}); err != nil {
+		log.Printf("Warning: Failed to increment downloads for %v: %v", ids, err)
+	}
+}
+
+// WriteQuestionZip streams every page of a question into a ZIP archive as page-N.<ext>.
+// Pages are fetched one at a time so the archive is never held in memory.
+func WriteQuestionZip(w io.Writer, question *models.Question) error {
+	archive := zip.NewWriter(w)
+
+	for _, image := range question.Images {
+		if _, err := writePageToZip(archive, &image, fmt.Sprintf("page-%d", image.Page)); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// BuildQuestionPDF renders each page of a question on its own PDF page, sized to the image
+func BuildQuestionPDF(question *models.Question) (*bytes.Buffer, error) {
+	pdf := fpdf.New("P", "pt", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle(question.ID, true)
+
+	for _, image := range question.Images {
+		name, info, err := registerPage(pdf, &image)
+		if err != nil {
+			return nil, err
+		}
+
+		width, height := info.Extent()
+		pdf.AddPageFormat("P", fpdf.SizeType{Wd: width, Ht: height})
+		pdf.ImageOptions(name, 0, 0, width, height, false, fpdf.ImageOptions{}, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	return &buf, nil
+}
+
+// writePageToZip copies a single page into the archive under name plus the detected extension,
+// returning the full entry name
+func writePageToZip(archive *zip.Writer, image *models.QuestionImage, name string) (string, error) {
+	body, contentType, err := fetchPage(image)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	name += "." + extensionFor(contentType)
+	entry, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store, // Images are already compressed
+		Modified: image.CreatedAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(entry, body); err != nil {
+		return "", fmt.Errorf("failed to copy page %d: %w", image.Page, err)
+	}
+
+	return name, nil
+}
+
+// registerPage fetches a page and registers it with the PDF under a unique name
+func registerPage(pdf *fpdf.Fpdf, image *models.QuestionImage) (string, *fpdf.ImageInfoType, error) {
+	body, contentType, err := fetchPage(image)
+	if err != nil {
+		return "", nil, err
+	}
+	defer body.Close()
+
+	var reader io.Reader = body
+	// The PDF can't embed WebP, and only Cloudinary converts on delivery, so other stores' WebP pages are re-encoded here
+	if contentType == "image/webp" {
+		converted, err := webpToJPEG(body)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to convert page %d: %w", image.Page, err)
+		}
+		reader, contentType = converted, "image/jpeg"
+	}
+
+	imageType := map[string]string{"image/jpeg": "JPG", "image/png": "PNG", "image/gif": "GIF"}[contentType]
+	if imageType == "" {
+		return "", nil, models.NewValidationError(fmt.Sprintf("Page %d has unsupported type %s", image.Page, contentType))
+	}
+
+	name := fmt.Sprintf("page-%d-%d", image.Page, image.ID)
+	info := pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: imageType}, reader)
+	if err := pdf.Error(); err != nil {
+		return "", nil, fmt.Errorf("failed to embed page %d: %w", image.Page, err)
+	}
+
+	return name, info, nil
+}
+
+// webpToJPEG decodes a WebP page and re-encodes it as JPEG
+func webpToJPEG(body io.Reader) (io.Reader, error) {
+	decoded, err := webp.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, decoded, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// fetchPage opens a page for reading, straight from storage where the store allows it and otherwise
+// over HTTP, asking storage for JPEG where it can convert.
+// The returned content type is sniffed from the first bytes rather than trusted from headers.
+func fetchPage(image *models.QuestionImage) (io.ReadCloser, string, error) {
+	var file io.ReadCloser
+	if image.PublicID != "" {
+		opened, ok, err := openStoredFile(image.PublicID)
+		if err != nil {
+			return nil, "", models.NewNetworkError(fmt.Sprintf("Failed to open page %d: %v", image.Page, err))
+		}
+		if ok {
+			file = opened
+		}
+	}
+
+	if file == nil {
+		url := image.URL
+		if image.PublicID != "" {
+			if jpegURL, err := BuildJPEGURL(image.PublicID); err == nil {
+				url = jpegURL
+			}
+		}
+
+		resp, err := pageClient.Get(url)
+		if err != nil {
+			return nil, "", models.NewNetworkError(fmt.Sprintf("Failed to fetch page %d: %v", image.Page, err))
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, "", models.NewNetworkError(fmt.Sprintf("Failed to fetch page %d: status %d", image.Page, resp.StatusCode))
+		}
+		file = resp.Body
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		file.Close()
+		return nil, "", models.NewNetworkError(fmt.Sprintf("Failed to read page %d: %v", image.Page, err))
+	}
+	head = head[:n]
+
+	body := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), file), file}
+
+	return body, http.DetectContentType(head), nil
+}
+
+// extensionFor maps a sniffed image content type to a file extension
+func extensionFor(contentType string) string {
+	switch contentType {
+	case "image/png":
+		return "png"
+	case "image/gif":
+		return "gif"
+	case "image/webp":
+		return "webp"
+	default:
+		return "jpg"
+	}
+}
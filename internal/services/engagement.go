@@ -0,0 +1,144 @@
+package services
+
+import (
+	"qb/pkg/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Trending ranking: a download or bookmark says more than a view, and a day's
+// engagement counts half as much every trendingHalfLifeDays
+const (
+	viewWeight           = 1.0
+	downloadWeight       = 3.0
+	bookmarkWeight       = 5.0
+	trendingHalfLifeDays = 3.0
+	defaultTrendingDays  = 14
+)
+
+// newEngagementEvent builds an event for the question, attributing it to the user when signed in
+func newEngagementEvent(questionID, userID string, eventType models.EngagementType) models.EngagementEvent {
+	event := models.EngagementEvent{QuestionID: questionID, Type: eventType, CreatedAt: time.Now()}
+	if userID != "" {
+		event.UserID = &userID
+	}
+	return event
+}
+
+// recordEngagement appends the events and adds them to the daily aggregates of the day they happened
+func recordEngagement(tx *gorm.DB, events []models.EngagementEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := tx.CreateInBatches(&events, 500).Error; err != nil {
+		return err
+	}
+
+	type statKey struct {
+		questionID string
+		day        time.Time
+	}
+	stats := make(map[statKey]*models.QuestionDailyStat)
+	var order []statKey
+	for _, event := range events {
+		created := event.CreatedAt.UTC()
+		key := statKey{event.QuestionID, time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)}
+		stat, ok := stats[key]
+		if !ok {
+			stat = &models.QuestionDailyStat{QuestionID: key.questionID, Day: key.day}
+			stats[key] = stat
+			order = append(order, key)
+		}
+		switch event.Type {
+		case models.EngagementTypeView:
+			stat.Views++
+		case models.EngagementTypeDownload:
+			stat.Downloads++
+		case models.EngagementTypeBookmark:
+			stat.Bookmarks++
+		}
+	}
+
+	rows := make([]models.QuestionDailyStat, 0, len(order))
+	for _, key := range order {
+		rows = append(rows, *stats[key])
+	}
+
+	// Existing days are incremented rather than overwritten
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"views":     gorm.Expr("views + VALUES(views)"),
+			"downloads": gorm.Expr("downloads + VALUES(downloads)"),
+			"bookmarks": gorm.Expr("bookmarks + VALUES(bookmarks)"),
+		}),
+	}).Create(&rows).Error
+}
+
+// GetTrendingQuestions ranks the questions the user can see by engagement over the last few days,
+// with each day's weighted engagement halving every trendingHalfLifeDays
+func GetTrendingQuestions(filter models.TrendingFilterDTO, userID, role string) ([]models.TrendingQuestion, error) {
+	if err := valS.Struct(filter); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	page, limit := clampPage(filter.Page, filter.Limit)
+	days := filter.Days
+	if days == 0 {
+		days = defaultTrendingDays
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	score := gorm.Expr("SUM((question_daily_stats.views * ? + question_daily_stats.downloads * ? + question_daily_stats.bookmarks * ?) * POW(0.5, DATEDIFF(?, question_daily_stats.day) / ?))",
+		viewWeight, downloadWeight, bookmarkWeight, today, trendingHalfLifeDays)
+
+	query := db.Table("question_daily_stats").
+		Select("question_daily_stats.question_id AS id, ? AS score", score).
+		Joins("JOIN questions ON questions.id = question_daily_stats.question_id").
+		Scopes(visibleTo(userID, role)).
+		Where("question_daily_stats.day >= ?", since)
+
+	if filter.DepartmentID != "" {
+		query = query.Where("questions.course_id IN (?)",
+			db.Table("department_courses").Select("course_id").Where("department_id = ?", filter.DepartmentID))
+	}
+	if filter.Level != 0 {
+		query = query.Joins("JOIN courses ON courses.id = questions.course_id").
+			Where("courses.level_id = ?", filter.Level)
+	}
+
+	offset := (page - 1) * limit
+
+	var hits []searchHit
+	if err := query.Group("question_daily_stats.question_id").
+		Order("score DESC, id ASC").
+		Offset(offset).Limit(limit).
+		Scan(&hits).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	var questions []models.Question
+	if err := db.Preload("Course").Preload("Session").Preload("Images", orderedImages).
+		Where("id IN ?", hitIDs(hits)).Find(&questions).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	byID := make(map[string]models.Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	results := make([]models.TrendingQuestion, 0, len(hits))
+	for _, hit := range hits {
+		if question, ok := byID[hit.ID]; ok {
+			results = append(results, models.TrendingQuestion{Question: question, Score: hit.Score})
+		}
+	}
+
+	return results, nil
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"log"
 	"qb/pkg/models"
 	"strconv"
 
@@ -46,7 +47,7 @@ func (s *ErrorService) Db(err error, resourceContext ...string) error {
 	}
 	
 	// Log unhandled database error
-	fmt.Printf("Unhandled database error: %v\n", err)
+	log.Printf("Unhandled database error: %v", err)
 	return models.ErrDatabase
 }
 
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register decoders for reading page dimensions
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	_ "golang.org/x/image/webp"
+)
+
+// localStore keeps pages as plain files under a directory that the server exposes on FilesRoute.
+// Images are stored as uploaded, so JPEG conversion and size optimisation are not available.
+type localStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore creates a store that writes under dir and builds URLs from baseURL
+func NewLocalStore(dir, baseURL string) (ObjectStore, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, tempFolder), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	store := &localStore{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}
+
+	// Start cleanup goroutine for abandoned temp uploads
+	go store.startTempCleanup()
+
+	return store, nil
+}
+
+// LocalStaticDir returns the directory to serve on FilesRoute when the local store is in use
+func LocalStaticDir() (string, bool) {
+	store, ok := objS.(*localStore)
+	if !ok {
+		return "", false
+	}
+	return store.dir, true
+}
+
+// localStorageDir returns the directory the local store writes to
+func localStorageDir() string {
+	return utils.GetEnv("STORAGE_LOCAL_DIR", "./uploads")
+}
+
+// UploadTemp writes an image into the temporary folder, named after a fresh UUID and its detected type
+func (s *localStore) UploadTemp(ctx context.Context, file io.Reader, requestID string) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+
+	extension := imageExtensions[http.DetectContentType(head)]
+	if extension == "" {
+		return "", fmt.Errorf("unsupported file type")
+	}
+
+	key := fmt.Sprintf("%s/%s.%s", tempFolder, uuid.New().String(), extension)
+	if err := s.write(key, io.MultiReader(bytes.NewReader(head), file)); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// Promote moves a temp file into the permanent folder and reads back its size and dimensions
+func (s *localStore) Promote(ctx context.Context, tempKey, folder string) (*models.ImageAsset, error) {
+	if !strings.HasPrefix(tempKey, tempFolder+"/") {
+		return nil, fmt.Errorf("%s is not a temporary upload", tempKey)
+	}
+
+	key := folder + extractFilenameFromPublicID(tempKey)
+	if err := s.rename(tempKey, key); err != nil {
+		return nil, fmt.Errorf("failed to move file to permanent location: %w", err)
+	}
+
+	return s.describe(key)
+}
+
+// Move renames a permanent file, removing the old folder once it is empty
+func (s *localStore) Move(ctx context.Context, fromKey, toKey string) (string, error) {
+	if err := s.rename(fromKey, toKey); err != nil {
+		return "", err
+	}
+
+	if fromDir, err := s.path(folderOf(fromKey)); err == nil {
+		os.Remove(fromDir) // Fails harmlessly while other pages remain
+	}
+
+	return s.URL(toKey)
+}
+
+// Delete removes a single file; a missing file is not an error
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every file and folder under the prefix
+func (s *localStore) DeletePrefix(ctx context.Context, prefix string) error {
+	dir, err := s.path(folderOf(prefix))
+	if err != nil {
+		return err
+	}
+	namePrefix := strings.TrimPrefix(prefix, folderOf(prefix))
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), namePrefix) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to delete files under %s: %w", prefix, err)
+		}
+	}
+
+	// A whole folder was asked for, so drop it too
+	if namePrefix == "" {
+		os.Remove(dir)
+	}
+
+	return nil
+}
+
+// URL builds the public URL of a file
+func (s *localStore) URL(key string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+// JPEGURL returns the plain URL, since files are served exactly as uploaded; PDFs convert WebP pages themselves
+func (s *localStore) JPEGURL(key string) (string, error) {
+	return s.URL(key)
+}
+
+// Open reads a file from disk
+func (s *localStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// path maps a key onto the storage directory. Keys with empty or parent segments are refused,
+// so a key can neither escape the directory nor widen a prefix (qb_questions//) to a whole root.
+func (s *localStore) path(key string) (string, error) {
+	segments := strings.Split(strings.TrimSuffix(key, "/"), "/")
+	for _, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." || strings.Contains(segment, `\`) {
+			return "", fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// write stores the reader's contents under the key, creating folders as needed
+func (s *localStore) write(key string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create folder for %s: %w", key, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", key, err)
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return file.Close()
+}
+
+// rename moves a file from one key to another, creating the destination folder as needed
+func (s *localStore) rename(fromKey, toKey string) error {
+	from, err := s.path(fromKey)
+	if err != nil {
+		return err
+	}
+	to, err := s.path(toKey)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+// describe reads a stored file's metadata; dimensions stay zero for formats without a decoder
+func (s *localStore) describe(key string) (*models.ImageAsset, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	url, err := s.URL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &models.ImageAsset{
+		PublicID: key,
+		URL:      url,
+		Bytes:    int(info.Size()),
+		Format:   utils.FormatFromURL(key),
+	}
+	if config, _, err := image.DecodeConfig(file); err == nil {
+		asset.Width = config.Width
+		asset.Height = config.Height
+	}
+
+	return asset, nil
+}
+
+// startTempCleanup periodically deletes temp uploads that outlived tempUploadTTL
+func (s *localStore) startTempCleanup() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.cleanupTempUploads()
+	}
+}
+
+// cleanupTempUploads deletes temp uploads older than tempUploadTTL
+func (s *localStore) cleanupTempUploads() {
+	dir := filepath.Join(s.dir, tempFolder)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("Warning: Failed to list temp uploads: %v", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-tempUploadTTL)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			log.Printf("Warning: Failed to delete expired temp upload %s: %v", entry.Name(), err)
+		}
+	}
+}
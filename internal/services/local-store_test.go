@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorePath(t *testing.T) {
+	store := &localStore{dir: filepath.FromSlash("/srv/uploads"), baseURL: "https://qb.example/files"}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"temp upload", "qb_temp_uploads/a.png", "/srv/uploads/qb_temp_uploads/a.png", false},
+		{"question page", "qb_questions/csc201-2023-t1/a.jpg", "/srv/uploads/qb_questions/csc201-2023-t1/a.jpg", false},
+		{"folder with trailing slash", "qb_questions/csc201-2023-t1/", "/srv/uploads/qb_questions/csc201-2023-t1", false},
+		{"parent segment", "qb_questions/../../etc/passwd", "", true},
+		{"parent only", "..", "", true},
+		{"current segment", "qb_questions/./a.jpg", "", true},
+		{"empty segment widens a prefix", "qb_questions//", "", true},
+		{"leading slash", "/etc/passwd", "", true},
+		{"empty key", "", "", true},
+		{"backslash", `qb_questions\..\secret`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.path(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("path(%q) = %q, want an error", tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("path(%q): %v", tt.key, err)
+			}
+			if want := filepath.FromSlash(tt.want); got != want {
+				t.Fatalf("path(%q) = %q, want %q", tt.key, got, want)
+			}
+		})
+	}
+}
+
+func TestLocalStoreURLRejectsBadKeys(t *testing.T) {
+	store := &localStore{dir: t.TempDir(), baseURL: "https://qb.example/files"}
+
+	url, err := store.URL("qb_questions/csc201-2023-t1/a.jpg")
+	if err != nil || url != "https://qb.example/files/qb_questions/csc201-2023-t1/a.jpg" {
+		t.Fatalf("URL() = %q, %v", url, err)
+	}
+
+	if _, err := store.URL("qb_questions/../../a.jpg"); err == nil {
+		t.Fatal("URL() accepted a key that escapes the storage directory")
+	}
+}
+
+func TestLocalStoreUploadPromoteOpen(t *testing.T) {
+	store := &localStore{dir: t.TempDir(), baseURL: "https://qb.example/files"}
+	ctx := context.Background()
+
+	// The PNG signature is enough for content sniffing
+	content := "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 600)
+
+	tempKey, err := store.UploadTemp(ctx, strings.NewReader(content), "request")
+	if err != nil {
+		t.Fatalf("UploadTemp: %v", err)
+	}
+	if !strings.HasPrefix(tempKey, tempFolder+"/") || !strings.HasSuffix(tempKey, ".png") {
+		t.Fatalf("UploadTemp key = %q", tempKey)
+	}
+
+	asset, err := store.Promote(ctx, tempKey, questionFolder("csc201-2023-t1"))
+	if err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	if asset.Bytes != len(content) || !strings.HasPrefix(asset.PublicID, questionsRoot+"csc201-2023-t1/") {
+		t.Fatalf("Promote asset = %+v", asset)
+	}
+	if _, err := os.Stat(filepath.Join(store.dir, filepath.FromSlash(tempKey))); !os.IsNotExist(err) {
+		t.Fatalf("temp file still exists after Promote: %v", err)
+	}
+
+	file, err := store.Open(ctx, asset.PublicID)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+	read, err := io.ReadAll(file)
+	if err != nil || string(read) != content {
+		t.Fatalf("Open read %d bytes, %v", len(read), err)
+	}
+
+	if _, err := store.Promote(ctx, asset.PublicID, questionFolder("other")); err == nil {
+		t.Fatal("Promote accepted a key outside the temporary folder")
+	}
+	if _, err := store.UploadTemp(ctx, strings.NewReader("plain text"), "request"); err == nil {
+		t.Fatal("UploadTemp accepted a file that is not an image")
+	}
+}
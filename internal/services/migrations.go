@@ -0,0 +1,57 @@
+package services
+
+import (
+	"log"
+	"qb/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// MigrateQuestionIDs renames questions whose ID no longer matches generateQuestionID, moving their
+// images to the new folder. It needs storage access, so it runs here rather than with the database
+// migrations. It must finish before the router starts, since renames and asset moves aren't safe
+// alongside live uploads and edits. Questions that fail are logged and picked up again on the next start.
+func MigrateQuestionIDs() {
+	var stale []models.Question
+	var batch []models.Question
+	err := db.Model(&models.Question{}).Select("id", "course_id", "session_id", "type", "paper").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			for _, question := range batch {
+				if question.ID != generateQuestionID(question.CourseID, question.SessionID, question.Type, question.Paper) {
+					stale = append(stale, question)
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		log.Printf("Warning: Failed to scan question IDs: %v", err)
+		return
+	}
+
+	migrated := 0
+	for _, candidate := range stale {
+		newID := generateQuestionID(candidate.CourseID, candidate.SessionID, candidate.Type, candidate.Paper)
+
+		var count int64
+		if err := db.Model(&models.Question{}).Where("id = ?", newID).Count(&count).Error; err != nil || count > 0 {
+			log.Printf("Warning: Cannot rename question %s to %s: ID already taken or lookup failed", candidate.ID, newID)
+			continue
+		}
+
+		var question models.Question
+		if err := db.Preload("Images", orderedImages).Where("id = ?", candidate.ID).First(&question).Error; err != nil {
+			log.Printf("Warning: Failed to load question %s for renaming: %v", candidate.ID, err)
+			continue
+		}
+
+		if err := applyQuestionUpdate(&question, newID, map[string]interface{}{}); err != nil {
+			log.Printf("Warning: Failed to rename question %s to %s: %v", candidate.ID, newID, err)
+			continue
+		}
+		migrated++
+	}
+
+	if len(stale) > 0 {
+		log.Printf("Migrated %d of %d question IDs to the current scheme", migrated, len(stale))
+	}
+}
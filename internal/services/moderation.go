@@ -0,0 +1,172 @@
+package services
+
+import (
+	"qb/pkg/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetPendingQuestions lists questions awaiting moderation, oldest first
+func GetPendingQuestions(courseID, sessionID, uploaderID string, page, limit int) ([]models.Question, error) {
+	var questions []models.Question
+
+	query := db.Preload("Course").Preload("Session").Preload("Uploader").Preload("Images", orderedImages).
+		Where("moderation_status = ?", models.ModerationStatusPending)
+
+	if courseID != "" {
+		query = query.Where("course_id = ?", courseID)
+	}
+
+	if sessionID != "" {
+		query = query.Where("session_id = ?", sessionID)
+	}
+
+	if uploaderID != "" {
+		query = query.Where("uploader_id = ?", uploaderID)
+	}
+
+	offset := (page - 1) * limit
+
+	if err := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&questions).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	// Never expose uploader password hashes
+	for i := range questions {
+		if questions[i].Uploader != nil {
+			questions[i].Uploader.Password = nil
+		}
+	}
+
+	return questions, nil
+}
+
+// ApproveQuestion publishes a question; the reason is optional
+func ApproveQuestion(id, actorID string, input models.ModerateQuestionDTO) (*models.Question, error) {
+	return moderateQuestion(id, actorID, models.ModerationActionApprove, models.ModerationStatusApproved, input)
+}
+
+// RejectQuestion hides a question from the public and records why, so the uploader can see it
+func RejectQuestion(id, actorID string, input models.ModerateQuestionDTO) (*models.Question, error) {
+	if strings.TrimSpace(input.Reason) == "" {
+		return nil, errS.Invalid("A reason is required when rejecting a question")
+	}
+	return moderateQuestion(id, actorID, models.ModerationActionReject, models.ModerationStatusRejected, input)
+}
+
+// GetMyQuestions lists every question uploaded by the user, whatever its moderation status
+func GetMyQuestions(userID string, page, limit int) ([]models.Question, error) {
+	var questions []models.Question
+
+	offset := (page - 1) * limit
+
+	if err := db.Preload("Images", orderedImages).Where("uploader_id = ?", userID).Order("created_at DESC").
+		Offset(offset).Limit(limit).Find(&questions).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return questions, nil
+}
+
+// GetModerationHistory lists a question's moderation events, newest first.
+// Only the uploader and admins may read it.
+func GetModerationHistory(id, userID, role string) ([]models.ModerationEvent, error) {
+	var question models.Question
+	if err := db.Select("id", "uploader_id").Where("id = ?", id).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+
+	if !canManageQuestion(&question, userID, role) {
+		return nil, models.ErrForbidden
+	}
+
+	var events []models.ModerationEvent
+	if err := db.Preload("Actor", publicAuthor).Where("question_id = ?", id).Order("created_at DESC, id DESC").Find(&events).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return events, nil
+}
+
+// moderateQuestion moves a question to the target moderation status and logs the transition
+func moderateQuestion(id, actorID string, action models.ModerationAction, status models.ModerationStatus, input models.ModerateQuestionDTO) (*models.Question, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	var question models.Question
+	if err := db.Where("id = ?", id).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+
+	if question.ModerationStatus == status {
+		return nil, errS.Invalid("Question is already " + strings.ToLower(string(status)))
+	}
+
+	var reason *string
+	if trimmed := strings.TrimSpace(input.Reason); trimmed != "" {
+		reason = &trimmed
+	}
+	now := time.Now()
+
+	before := question
+	question.Approved = status == models.ModerationStatusApproved
+	question.ModerationStatus = status
+	question.ModerationReason = reason
+	question.ModeratedAt = &now
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"approved":          question.Approved,
+			"moderation_status": status,
+			"moderation_reason": reason,
+			"moderated_at":      now,
+		}
+		if err := tx.Model(&question).Updates(updates).Error; err != nil {
+			return err
+		}
+		return recordModerationEvent(tx, action, &before, &question, actorID, reason)
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &question, nil
+}
+
+// recordModerationEvent stores the transition between two states of the same question.
+// Nothing is written when neither the approval, moderation nor processing status changed.
+func recordModerationEvent(tx *gorm.DB, action models.ModerationAction, before, after *models.Question, actorID string, reason *string) error {
+	if before.Approved == after.Approved &&
+		before.ModerationStatus == after.ModerationStatus &&
+		stringPtrEqual(before.ProcessingStatus, after.ProcessingStatus) {
+		return nil
+	}
+
+	event := models.ModerationEvent{
+		QuestionID:           &after.ID,
+		Action:               action,
+		FromApproved:         before.Approved,
+		ToApproved:           after.Approved,
+		FromStatus:           before.ModerationStatus,
+		ToStatus:             after.ModerationStatus,
+		FromProcessingStatus: before.ProcessingStatus,
+		ToProcessingStatus:   after.ProcessingStatus,
+		Reason:               reason,
+	}
+	if actorID != "" {
+		event.ActorID = &actorID
+	}
+
+	return tx.Create(&event).Error
+}
+
+// stringPtrEqual compares two optional strings by value
+func stringPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	tempFolder    = "qb_temp_uploads"
+	questionsRoot = "qb_questions/"
+	solutionsRoot = "qb_solutions/"
+
+	// tempUploadTTL is how long a staged upload waits to be attached to a question or solution
+	tempUploadTTL = 24 * time.Hour
+
+	// FilesRoute is where the server exposes stored pages for the local and S3 stores
+	FilesRoute = "/files"
+)
+
+// ObjectStore is where page images live. Keys are folder-style paths such as qb_temp_uploads/<file>
+// or qb_questions/<id>/<file>, stored as PublicID; how they map to files and URLs is up to the store.
+type ObjectStore interface {
+	// UploadTemp stores a validated image under the temporary folder and returns its key
+	UploadTemp(ctx context.Context, file io.Reader, requestID string) (string, error)
+	// Promote moves a temporary image into a permanent folder (with trailing slash), keeping its file name
+	Promote(ctx context.Context, tempKey, folder string) (*models.ImageAsset, error)
+	// Move renames a permanent image and returns its new URL
+	Move(ctx context.Context, fromKey, toKey string) (string, error)
+	// Delete removes a single image
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every image whose key starts with the prefix; an empty prefix match is a no-op
+	DeletePrefix(ctx context.Context, prefix string) error
+	// URL builds the public URL of an image
+	URL(key string) (string, error)
+	// JPEGURL builds a URL that serves the image as JPEG where the store can convert, else its plain URL
+	JPEGURL(key string) (string, error)
+}
+
+// InitObjectStore picks the storage backend from STORAGE_BACKEND (cloudinary, s3 or local).
+// Without it, Cloudinary is used when CLOUDINARY_URL is set and local disk otherwise, so the server can start offline.
+func InitObjectStore() {
+	backend := utils.GetEnv("STORAGE_BACKEND", "")
+	if backend == "" {
+		backend = "local"
+		if utils.GetEnv("CLOUDINARY_URL", "") != "" {
+			backend = "cloudinary"
+		}
+	}
+
+	var err error
+	switch backend {
+	case "cloudinary":
+		objS, err = NewCloudinaryStore(utils.GetEnvFatal("CLOUDINARY_URL"))
+	case "s3":
+		objS, err = NewS3Store(s3ConfigFromEnv())
+	case "local":
+		objS, err = NewLocalStore(localStorageDir(), storagePublicURL())
+	default:
+		log.Fatalf("Unknown STORAGE_BACKEND %q, expected cloudinary, s3 or local", backend)
+	}
+	if err != nil {
+		log.Fatalf("Failed to initialize %s storage: %v", backend, err)
+	}
+
+	log.Printf("Using %s storage for uploads", backend)
+}
+
+// presigner is implemented by stores whose objects are private and read through short-lived links
+type presigner interface {
+	PresignGet(ctx context.Context, key string) (string, error)
+}
+
+// ServesPresignedFiles reports whether FilesRoute should redirect to presigned links
+func ServesPresignedFiles() bool {
+	_, ok := objS.(presigner)
+	return ok
+}
+
+// PresignedFileURL returns a short-lived link to a stored page. Only keys under the
+// upload folders are signed, so the route can't be used to read anything else in the bucket.
+func PresignedFileURL(key string) (string, error) {
+	store, ok := objS.(presigner)
+	if !ok {
+		return "", models.ErrNotFound
+	}
+
+	if !strings.HasPrefix(key, tempFolder+"/") && !strings.HasPrefix(key, questionsRoot) && !strings.HasPrefix(key, solutionsRoot) {
+		return "", models.ErrNotFound
+	}
+	if strings.Contains(key, "..") {
+		return "", models.ErrNotFound
+	}
+
+	url, err := store.PresignGet(context.Background(), key)
+	if err != nil {
+		return "", models.NewNetworkError(err.Error())
+	}
+	return url, nil
+}
+
+// storagePublicURL returns the absolute URL FilesRoute is reachable under. It is required because
+// the URLs built from it are stored with each page, so a guessed default would outlive the deployment.
+func storagePublicURL() string {
+	return utils.GetEnvFatal("STORAGE_PUBLIC_URL")
+}
+
+// fileOpener is implemented by stores that can read a page directly instead of over HTTP
+type fileOpener interface {
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// openStoredFile reads a page straight from the store, when the store supports it
+func openStoredFile(key string) (io.ReadCloser, bool, error) {
+	store, ok := objS.(fileOpener)
+	if !ok {
+		return nil, false, nil
+	}
+	file, err := store.Open(context.Background(), key)
+	return file, true, err
+}
+
+// UploadFileToTemp uploads a single image file to the temporary folder and returns its key
+func UploadFileToTemp(fileHeader *multipart.FileHeader, requestID string) (string, error) {
+	if objS == nil {
+		return "", models.ErrInternal
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return "", errS.Invalid(fmt.Sprintf("Failed to open file: %v", err))
+	}
+	defer file.Close()
+
+	return objS.UploadTemp(context.Background(), file, requestID)
+}
+
+// MoveFileToPermanent moves an image from the temporary folder into the given permanent folder and returns its stored metadata
+func MoveFileToPermanent(tempPublicID, folder string) (*models.ImageAsset, error) {
+	if objS == nil {
+		return nil, models.ErrInternal
+	}
+	return objS.Promote(context.Background(), tempPublicID, folder)
+}
+
+// MoveQuestionAssets renames every page of a question into qb_questions/<newQuestionID>/,
+// updating PublicID and URL on the given images in place. Pages outside the old question folder are
+// left untouched. If a rename fails, pages already moved are renamed back and restored.
+func MoveQuestionAssets(oldQuestionID, newQuestionID string, images []models.QuestionImage) error {
+	if objS == nil {
+		return models.ErrInternal
+	}
+
+	ctx := context.Background()
+	oldFolder := questionFolder(oldQuestionID)
+
+	type move struct {
+		index           int
+		fromID, fromURL string
+	}
+	var moved []move
+
+	for i := range images {
+		image := &images[i]
+		if !strings.HasPrefix(image.PublicID, oldFolder) {
+			continue
+		}
+
+		newPublicID := questionFolder(newQuestionID) + extractFilenameFromPublicID(image.PublicID)
+		newURL, err := objS.Move(ctx, image.PublicID, newPublicID)
+		if err != nil {
+			for _, m := range moved {
+				if _, undoErr := objS.Move(ctx, images[m.index].PublicID, m.fromID); undoErr != nil {
+					log.Printf("Warning: Failed to restore %s after aborted move: %v", m.fromID, undoErr)
+				}
+				images[m.index].PublicID = m.fromID
+				images[m.index].URL = m.fromURL
+			}
+			return fmt.Errorf("failed to move %s: %w", image.PublicID, err)
+		}
+
+		moved = append(moved, move{index: i, fromID: image.PublicID, fromURL: image.URL})
+		image.PublicID = newPublicID
+		image.URL = newURL
+	}
+
+	return nil
+}
+
+// DeleteQuestionAssets removes every permanent page of a question. Deleting an already-empty folder is a no-op.
+func DeleteQuestionAssets(questionID string) error {
+	if objS == nil {
+		return models.ErrInternal
+	}
+	return objS.DeletePrefix(context.Background(), questionFolder(questionID))
+}
+
+// DeleteSolutionAssets removes every permanent page of a solution, the same way as DeleteQuestionAssets
+func DeleteSolutionAssets(solutionID string) error {
+	if objS == nil {
+		return models.ErrInternal
+	}
+	return objS.DeletePrefix(context.Background(), solutionFolder(solutionID))
+}
+
+// DestroyQuestionImage removes the stored image behind a single page.
+// Pages that do not live in a question folder (e.g. seeded placeholders) are ignored.
+func DestroyQuestionImage(image *models.QuestionImage) error {
+	if objS == nil {
+		return models.ErrInternal
+	}
+
+	if !strings.HasPrefix(image.PublicID, questionsRoot) {
+		return nil
+	}
+	return objS.Delete(context.Background(), image.PublicID)
+}
+
+// BuildImageURL constructs the public URL of a stored image from its public ID
+func BuildImageURL(publicID string) (string, error) {
+	if objS == nil {
+		return "", models.ErrInternal
+	}
+
+	url, err := objS.URL(publicID)
+	if err != nil {
+		return "", errS.Invalid(fmt.Sprintf("Failed to generate image URL: %v", err))
+	}
+
+	return url, nil
+}
+
+// BuildJPEGURL constructs a URL that delivers the stored image as JPEG where the store supports it
+func BuildJPEGURL(publicID string) (string, error) {
+	if objS == nil {
+		return "", models.ErrInternal
+	}
+	return objS.JPEGURL(publicID)
+}
+
+// ValidateImageFile validates uploaded image files
+func ValidateImageFile(fileHeader *multipart.FileHeader) error {
+	// Check file size (10MB limit)
+	if fileHeader.Size > 10*1024*1024 {
+		return errS.Invalid("File size exceeds 10MB limit")
+	}
+	
+	// Check MIME type
+	file, err := fileHeader.Open()
+	if err != nil {
+		return errS.Invalid(fmt.Sprintf("Failed to open file: %v", err))
+	}
+	defer file.Close()
+	
+	// Read first 512 bytes to detect content type
+	buffer := make([]byte, 512)
+	_, err = file.Read(buffer)
+	if err != nil {
+		return errS.Invalid(fmt.Sprintf("Failed to read file content: %v", err))
+	}
+	
+	contentType := http.DetectContentType(buffer)
+	if imageExtensions[contentType] == "" {
+		return errS.Invalid(fmt.Sprintf("Unsupported file type: %s. Allowed types: JPEG, PNG, WebP", contentType))
+	}
+	
+	return nil
+}
+
+// imageExtensions lists the accepted upload types and the extension each is stored with
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/jpg":  "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// extractFilenameFromPublicID extracts the filename part from a public ID
+func extractFilenameFromPublicID(publicID string) string {
+	parts := strings.Split(publicID, "/")
+	return parts[len(parts)-1]
+}
+
+// folderOf returns the folder part of a key, with a trailing slash
+func folderOf(key string) string {
+	return key[:strings.LastIndex(key, "/")+1]
+}
+
+// questionFolder returns the permanent folder prefix for a question's pages, with a trailing slash
+func questionFolder(questionID string) string {
+	return fmt.Sprintf("%s%s/", questionsRoot, questionID)
+}
+
+// solutionFolder returns the permanent folder prefix for a solution's pages, with a trailing slash
+func solutionFolder(solutionID string) string {
+	return fmt.Sprintf("%s%s/", solutionsRoot, solutionID)
+}
+
+// DetectContentType mimics http.DetectContentType but can be overridden for testing
+var DetectContentType = func(data []byte) string {
+	// This would normally be http.DetectContentType(data)
+	// But we'll keep it simple for now and infer from file extension
+	return "image/jpeg" // Default for now, can be enhanced
+} 
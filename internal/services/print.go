@@ -0,0 +1,257 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// printCacheDir holds rendered print PDFs, one file per question and content fingerprint
+var printCacheDir string
+
+// InitPrintCache prepares the directory used to cache rendered print PDFs
+func InitPrintCache() {
+	printCacheDir = utils.GetEnv("PDF_CACHE_DIR", filepath.Join(os.TempDir(), "qb_pdf_cache"))
+	if err := os.MkdirAll(printCacheDir, 0o755); err != nil {
+		log.Printf("Warning: Failed to create PDF cache directory %s: %v", printCacheDir, err)
+	}
+}
+
+// GetPrintablePDF opens a print-ready PDF for the question, rendering it on a cache miss.
+// The cache key covers the pages and every field shown on the cover, so any change renders afresh.
+// The file is returned open, so an invalidation racing the response can't delete it from under the reader;
+// the caller closes it.
+func GetPrintablePDF(question *models.Question) (*os.File, error) {
+	path := filepath.Join(printCacheDir, fmt.Sprintf("%s-%s.pdf", question.ID, printFingerprint(question)))
+	if file, err := os.Open(path); err == nil {
+		return file, nil
+	}
+
+	pdf, err := buildPrintablePDF(question)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older renders of this question are stale now
+	InvalidatePrintCache(question.ID)
+
+	// Write then rename so concurrent readers never see a partial file
+	tmp, err := os.CreateTemp(printCacheDir, question.ID+"-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDF cache file: %w", err)
+	}
+	if err := pdf.Output(tmp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("failed to store PDF cache file: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to read PDF cache file: %w", err)
+	}
+
+	return tmp, nil
+}
+
+// InvalidatePrintCache removes every cached render of a question
+func InvalidatePrintCache(questionID string) {
+	if printCacheDir == "" {
+		return
+	}
+
+	matches, err := filepath.Glob(filepath.Join(printCacheDir, questionID+"-*.pdf"))
+	if err != nil {
+		return
+	}
+	for _, match := range matches {
+		if err := os.Remove(match); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warning: Failed to remove cached PDF %s: %v", match, err)
+		}
+	}
+}
+
+// printFingerprint hashes the pages and cover fields of a question
+func printFingerprint(question *models.Question) string {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "%s|%s|%s|%d|%s|%d|%s|%s|%s|", question.ID, question.CourseID, question.SessionID,
+		derefInt(question.TimeAllowed), question.Type, question.Paper, derefString(question.PaperLabel),
+		derefString(question.Lecturer), derefString(question.Tips))
+	if question.Course != nil {
+		fmt.Fprintf(h, "%s|", question.Course.Title)
+	}
+	if question.Session != nil {
+		fmt.Fprintf(h, "%d|%d|%s|", question.Session.StartDate, question.Session.EndDate, derefString(question.Session.Info))
+	}
+	for _, image := range question.Images {
+		fmt.Fprintf(h, "%d:%d:%s:%s|", image.ID, image.Page, image.PublicID, image.URL)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// buildPrintablePDF renders a cover page, one A4 page per image and the tips as an appendix
+func buildPrintablePDF(question *models.Question) (*fpdf.Fpdf, error) {
+	const margin = 36.0 // half an inch, in points
+
+	pdf := fpdf.New("P", "pt", "A4", "")
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+	pdf.SetTitle(question.ID, true)
+	pdf.SetCreator("qb-api", true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	writeCoverPage(pdf, question, tr)
+
+	a4 := pdf.GetPageSizeStr("A4")
+	for _, image := range question.Images {
+		name, info, err := registerPage(pdf, &image)
+		if err != nil {
+			return nil, err
+		}
+
+		// Landscape scans print on landscape pages instead of being shrunk
+		orientation, pageWidth, pageHeight := "P", a4.Wd, a4.Ht
+		if imageWidth, imageHeight := info.Extent(); imageWidth > imageHeight {
+			orientation, pageWidth, pageHeight = "L", a4.Ht, a4.Wd
+		}
+		pdf.AddPageFormat(orientation, a4)
+
+		width, height := fitWithin(info, pageWidth-2*margin, pageHeight-2*margin)
+		x := (pageWidth - width) / 2
+		y := (pageHeight - height) / 2
+		pdf.ImageOptions(name, x, y, width, height, false, fpdf.ImageOptions{}, 0, "")
+	}
+
+	if tips := strings.TrimSpace(derefString(question.Tips)); tips != "" {
+		pdf.AddPageFormat("P", a4)
+		pdf.SetFont("Helvetica", "B", 18)
+		pdf.CellFormat(0, 28, tr("Appendix: Tips"), "", 1, "L", false, 0, "")
+		pdf.Ln(8)
+		pdf.SetFont("Helvetica", "", 12)
+		pdf.MultiCell(0, 16, tr(tips), "", "L", false)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+
+	return pdf, nil
+}
+
+// writeCoverPage lays out the course, session and exam details of a question
+func writeCoverPage(pdf *fpdf.Fpdf, question *models.Question, tr func(string) string) {
+	pdf.AddPage()
+
+	courseTitle := question.CourseID
+	if question.Course != nil && question.Course.Title != "" {
+		courseTitle = question.Course.Title
+	}
+
+	pdf.SetY(180)
+	pdf.SetFont("Helvetica", "B", 28)
+	pdf.MultiCell(0, 34, tr(courseTitle), "", "C", false)
+	pdf.SetFont("Helvetica", "", 18)
+	pdf.CellFormat(0, 28, tr(strings.ToUpper(question.CourseID)), "", 1, "C", false, 0, "")
+	pdf.Ln(36)
+
+	paper := fmt.Sprintf("%s %d", humanize(string(question.Type)), question.Paper)
+	if label := derefString(question.PaperLabel); label != "" {
+		paper = label
+	}
+
+	details := [][2]string{{"Paper", paper}}
+	if question.Session != nil {
+		session := fmt.Sprintf("%d/%d", question.Session.StartDate, question.Session.EndDate)
+		if info := derefString(question.Session.Info); info != "" {
+			session = info
+		}
+		details = append(details, [2]string{"Session", session})
+	} else {
+		details = append(details, [2]string{"Session", question.SessionID})
+	}
+	if lecturer := derefString(question.Lecturer); lecturer != "" {
+		details = append(details, [2]string{"Lecturer", lecturer})
+	}
+	if question.TimeAllowed != nil {
+		details = append(details, [2]string{"Time allowed", formatMinutes(*question.TimeAllowed)})
+	}
+	details = append(details, [2]string{"Pages", fmt.Sprintf("%d", len(question.Images))})
+
+	pageWidth, _ := pdf.GetPageSize()
+	labelWidth := 120.0
+	left := (pageWidth - 2*labelWidth) / 2
+	for _, detail := range details {
+		pdf.SetX(left)
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.CellFormat(labelWidth, 22, tr(detail[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 13)
+		pdf.CellFormat(labelWidth*1.5, 22, tr(detail[1]), "", 1, "L", false, 0, "")
+	}
+}
+
+// fitWithin scales an image to the largest size that fits the box while keeping its aspect ratio
+func fitWithin(info *fpdf.ImageInfoType, maxWidth, maxHeight float64) (float64, float64) {
+	width, height := info.Extent()
+	scale := maxWidth / width
+	if heightScale := maxHeight / height; heightScale < scale {
+		scale = heightScale
+	}
+	return width * scale, height * scale
+}
+
+// formatMinutes renders a duration in minutes as e.g. "2 hours 30 minutes"
+func formatMinutes(minutes int) string {
+	hours, rest := minutes/60, minutes%60
+	var parts []string
+	if hours == 1 {
+		parts = append(parts, "1 hour")
+	} else if hours > 1 {
+		parts = append(parts, fmt.Sprintf("%d hours", hours))
+	}
+	if rest == 1 {
+		parts = append(parts, "1 minute")
+	} else if rest > 1 || hours == 0 {
+		parts = append(parts, fmt.Sprintf("%d minutes", rest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// humanize turns an enum value such as MOCK_EXAM into "Mock exam"
+func humanize(value string) string {
+	if value == "" {
+		return ""
+	}
+	value = strings.ToLower(strings.ReplaceAll(value, "_", " "))
+	return strings.ToUpper(value[:1]) + value[1:]
+}
+
+// derefString returns the value of an optional string, or "" when nil
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// derefInt returns the value of an optional int, or 0 when nil
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
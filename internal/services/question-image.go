@@ -0,0 +1,182 @@
+package services
+
+import (
+	"log"
+	"qb/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// ReorderQuestionImages sets a new page order; the IDs must be a permutation of the question's images
+func ReorderQuestionImages(id, userID, role string, input models.ReorderImagesDTO) (*models.Question, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	question, err := getManageableQuestion(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.QuestionImage, len(question.Images))
+	for _, image := range question.Images {
+		byID[image.ID] = image
+	}
+
+	ordered := make([]models.QuestionImage, 0, len(input.ImageIDs))
+	for _, imageID := range input.ImageIDs {
+		image, ok := byID[imageID]
+		if !ok {
+			return nil, errS.Invalid("imageIds must contain exactly the question's current images")
+		}
+		delete(byID, imageID)
+		ordered = append(ordered, image)
+	}
+	if len(byID) > 0 {
+		return nil, errS.Invalid("imageIds must contain exactly the question's current images")
+	}
+
+	question.Images = ordered
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return renumberPages(tx, question)
+	}); err != nil {
+		return nil, errS.Db(err)
+	}
+	InvalidatePrintCache(question.ID)
+
+	return question, nil
+}
+
+// RemoveQuestionImage removes a page and destroys its stored asset
+func RemoveQuestionImage(id, userID, role string, imageID uint) (*models.Question, error) {
+	question, err := getManageableQuestion(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := findQuestionImage(question, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := question.Images[index]
+
+	// Destroy first so a storage failure leaves the page listed and the call can be retried
+	if err := DestroyQuestionImage(&removed); err != nil {
+		return nil, models.NewNetworkError(err.Error())
+	}
+
+	images := make([]models.QuestionImage, 0, len(question.Images)-1)
+	images = append(images, question.Images[:index]...)
+	images = append(images, question.Images[index+1:]...)
+	question.Images = images
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&removed).Error; err != nil {
+			return err
+		}
+		return renumberPages(tx, question)
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+	InvalidatePrintCache(question.ID)
+
+	return question, nil
+}
+
+// ReplaceQuestionImage swaps a page for a single staged upload from /upload-images
+func ReplaceQuestionImage(id, userID, role string, imageID uint, input models.ReplaceImageDTO) (*models.Question, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	question, err := getManageableQuestion(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := findQuestionImage(question, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count before validating so a bad request does not consume the staged upload
+	successful := 0
+	for _, result := range input.UploadResults.Results {
+		if result.Error == "" && result.PublicID != "" {
+			successful++
+		}
+	}
+	if successful != 1 {
+		return nil, errS.Invalid("Exactly one uploaded image is required to replace a page")
+	}
+
+	tempPublicIDs, err := processUploadResults(input.UploadResults)
+	if err != nil {
+		return nil, err
+	}
+
+	finalImages, _ := processQuestionImages(tempPublicIDs, question.ID)
+	if len(finalImages) == 0 {
+		return nil, models.NewUploadError("Failed to move the replacement image to permanent storage")
+	}
+
+	replaced := question.Images[index]
+	replacement := finalImages[0]
+	replacement.Page = replaced.Page
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&replaced).Error; err != nil {
+			return err
+		}
+		return tx.Create(&replacement).Error
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	question.Images[index] = replacement
+	question.SyncImageLinks()
+	InvalidatePrintCache(question.ID)
+
+	// The new page is already live, so a leftover old asset is only logged
+	if err := DestroyQuestionImage(&replaced); err != nil {
+		log.Printf("Warning: Failed to destroy replaced image %s: %v", replaced.PublicID, err)
+	}
+
+	return question, nil
+}
+
+// findQuestionImage locates a page of the question by its ID, so edits never land on a page that
+// moved since the client read the question
+func findQuestionImage(question *models.Question, imageID uint) (int, error) {
+	var image models.QuestionImage
+	if err := db.Select("id").Where("question_id = ? AND id = ?", question.ID, imageID).First(&image).Error; err != nil {
+		return 0, errS.Db(err, "Image")
+	}
+
+	for i := range question.Images {
+		if question.Images[i].ID == image.ID {
+			return i, nil
+		}
+	}
+	return 0, models.ErrNotFound
+}
+
+// renumberPages stores 1..n as the page numbers of question.Images in their current order
+func renumberPages(tx *gorm.DB, question *models.Question) error {
+	for i := range question.Images {
+		image := &question.Images[i]
+		if image.Page == i+1 {
+			continue
+		}
+		image.Page = i + 1
+		if err := tx.Model(image).Update("page", image.Page).Error; err != nil {
+			return err
+		}
+	}
+
+	question.SyncImageLinks()
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"qb/pkg/models"
 	"strconv"
 	"strings"
@@ -13,50 +14,207 @@ import (
 
 // Question service functions using package-level dependencies
 
-// GetQuestions retrieves questions with optional filtering
-func GetQuestions(courseID, sessionID, questionType string, page, limit int) ([]models.Question, error) {
+const (
+	// DefaultPageSize is used when a listing doesn't ask for a limit
+	DefaultPageSize = 20
+	// MaxPageSize caps how many rows a single listing page can return
+	MaxPageSize = 100
+)
+
+// questionSortColumns maps the public sort keys to their columns
+var questionSortColumns = map[string]string{
+	"views":     "questions.views",
+	"downloads": "questions.downloads",
+	"createdAt": "questions.created_at",
+	// Rating sorts; readable and complete rank by net votes
+	"difficulty": "questions.difficulty",
+	"readable":   "(questions.readable_yes - questions.readable_no)",
+	"complete":   "(questions.complete_yes - questions.complete_no)",
+}
+
+// GetQuestions retrieves one page of questions matching the filter, along with pagination metadata
+func GetQuestions(filter models.QuestionFilterDTO, userID, role string) ([]models.Question, *models.PaginationMeta, error) {
+	if err := valS.Struct(filter); err != nil {
+		return nil, nil, errS.Invalid(err)
+	}
+	if filter.YearFrom != 0 && filter.YearTo != 0 && filter.YearFrom > filter.YearTo {
+		return nil, nil, &models.BusinessError{
+			Code:    400,
+			Message: "yearFrom cannot be after yearTo",
+		}
+	}
+
 	var questions []models.Question
+	page, limit := clampPage(filter.Page, filter.Limit)
 	
-	query := db
-	
-	// Add filtering for approved questions only (public endpoint)
-	// query = query.Where("approved = ?", true)
+	query := db.Model(&models.Question{}).Scopes(visibleTo(userID, role))
 	
 	// Optional filtering by course
-	if courseID != "" {
-		query = query.Where("course_id = ?", courseID)
+	if filter.CourseID != "" {
+		query = query.Where("questions.course_id = ?", filter.CourseID)
 	}
 	
 	// Optional filtering by session
-	if sessionID != "" {
-		query = query.Where("session_id = ?", sessionID)
+	if filter.SessionID != "" {
+		query = query.Where("questions.session_id = ?", filter.SessionID)
 	}
 	
 	// Optional filtering by type
-	if questionType != "" {
-		query = query.Where("type = ?", questionType)
+	if filter.Type != "" {
+		query = query.Where("questions.type = ?", filter.Type)
+	}
+
+	if filter.Paper != 0 {
+		query = query.Where("questions.paper = ?", filter.Paper)
+	}
+
+	if filter.Lecturer != "" {
+		query = query.Where("questions.lecturer LIKE ?", "%"+filter.Lecturer+"%")
+	}
+
+	// Department and faculty go through the join table as subqueries so a course
+	// offered by several departments doesn't show up more than once
+	if filter.DepartmentID != "" {
+		query = query.Where("questions.course_id IN (?)",
+			db.Table("department_courses").Select("course_id").Where("department_id = ?", filter.DepartmentID))
+	}
+	if filter.FacultyID != 0 {
+		query = query.Where("questions.course_id IN (?)",
+			db.Table("department_courses").Select("department_courses.course_id").
+				Joins("JOIN departments ON departments.id = department_courses.department_id").
+				Where("departments.faculty_id = ?", filter.FacultyID))
+	}
+
+	if filter.Level != 0 || filter.Semester != 0 {
+		query = query.Joins("JOIN courses ON courses.id = questions.course_id")
+		if filter.Level != 0 {
+			query = query.Where("courses.level_id = ?", filter.Level)
+		}
+		if filter.Semester != 0 {
+			query = query.Where("courses.semester = ?", filter.Semester)
+		}
+	}
+
+	if filter.YearFrom != 0 || filter.YearTo != 0 {
+		query = query.Joins("JOIN sessions ON sessions.id = questions.session_id")
+		if filter.YearFrom != 0 {
+			query = query.Where("sessions.start_date >= ?", filter.YearFrom)
+		}
+		if filter.YearTo != 0 {
+			query = query.Where("sessions.end_date <= ?", filter.YearTo)
+		}
+	}
+
+	// Sorting defaults to newest first; the id tiebreak keeps pages stable
+	column, ok := questionSortColumns[filter.Sort]
+	if !ok {
+		column = questionSortColumns["createdAt"]
+	}
+	order := "desc"
+	if filter.Order == "asc" {
+		order = "asc"
+	}
+	byCreatedAt := column == questionSortColumns["createdAt"]
+
+	if filter.Cursor != "" {
+		if !byCreatedAt {
+			return nil, nil, &models.BusinessError{
+				Code:    400,
+				Message: "Cursor pagination only supports sorting by createdAt",
+			}
+		}
+		return listQuestionsByCursor(query, filter.Cursor, order, limit)
+	}
+
+	// Counting mutates the statement, so the listing query needs its own copy
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, nil, errS.Db(err)
 	}
 	
 	// Pagination
 	offset := (page - 1) * limit
 	
-	if err := query.Offset(offset).Limit(limit).Find(&questions).Error; err != nil {
-		return nil, errS.Db(err)
+	if err := query.Select("questions.*").Preload("Images", orderedImages).
+		Order(column + " " + order).Order("questions.id " + order).
+		Offset(offset).Limit(limit).Find(&questions).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	meta := &models.PaginationMeta{
+		Total:   &total,
+		Page:    page,
+		Limit:   limit,
+		HasNext: int64(offset+len(questions)) < total,
 	}
 
-	return questions, nil
+	// Hand out a cursor so offset clients can switch to cursor paging from here
+	if byCreatedAt && meta.HasNext && len(questions) > 0 {
+		last := questions[len(questions)-1]
+		meta.NextCursor = encodeCursor(questionCursor{CreatedAt: last.CreatedAt, ID: last.ID, Order: order})
+	}
+
+	return questions, meta, nil
 }
 
-// GetQuestionByID retrieves a single question by ID and increments view count
-func GetQuestionByID(id string) (*models.Question, error) {
+// listQuestionsByCursor continues a createdAt-ordered listing from the row the cursor points at
+func listQuestionsByCursor(query *gorm.DB, token, order string, limit int) ([]models.Question, *models.PaginationMeta, error) {
+	cursor, err := decodeCursor(token, order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	comparison := "<"
+	if order == "asc" {
+		comparison = ">"
+	}
+
+	// Fetch one extra row to learn whether another page follows without counting
+	var questions []models.Question
+	if err := query.Select("questions.*").Preload("Images", orderedImages).
+		Where("(questions.created_at "+comparison+" ? OR (questions.created_at = ? AND questions.id "+comparison+" ?))",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ID).
+		Order("questions.created_at " + order).Order("questions.id " + order).
+		Limit(limit + 1).Find(&questions).Error; err != nil {
+		return nil, nil, errS.Db(err)
+	}
+
+	meta := &models.PaginationMeta{Limit: limit}
+	if len(questions) > limit {
+		questions = questions[:limit]
+		last := questions[len(questions)-1]
+		meta.HasNext = true
+		meta.NextCursor = encodeCursor(questionCursor{CreatedAt: last.CreatedAt, ID: last.ID, Order: order})
+	}
+
+	return questions, meta, nil
+}
+
+// GetQuestionByID retrieves a single question visible to the user and records the view
+func GetQuestionByID(id, userID, role, clientIP string) (*models.Question, error) {
 	var question models.Question
-	if err := db.Preload("Course").Preload("Session").Preload("Uploader").
-		Where("id = ? AND approved = ?", id, true).First(&question).Error; err != nil {
+	if err := db.Preload("Course").Preload("Session").Preload("Uploader").Preload("Images", orderedImages).
+		Preload("Solutions", approvedSolutions).Preload("Solutions.Images", orderedImages).Preload("Solutions.Author", publicAuthor).
+		Scopes(visibleTo(userID, role)).Where("questions.id = ?", id).First(&question).Error; err != nil {
 		return nil, errS.Db(err, "Question")
 	}
+	if question.Uploader != nil {
+		question.Uploader.Password = nil
+	}
+	if userID != "" {
+		myRating, err := getMyRating(question.ID, userID)
+		if err != nil {
+			return nil, errS.Db(err)
+		}
+		question.MyRating = myRating
+	}
 	
-	// Increment view count
-	db.Model(&question).Update("views", gorm.Expr("views + 1"))
+	// Only published questions count views, so uploaders checking their own drafts don't inflate them
+	if question.Approved {
+		viewCounter.RecordView(question.ID, userID, clientIP)
+	}
 	
 	return &question, nil
 }
@@ -73,18 +231,32 @@ func CreateQuestion(input models.CreateQuestionDTO, userID string) (*models.Ques
 		return nil, "", false, err
 	}
 
+	// Resolve the paper before the upload request is used up, so a conflict can be retried
+	if input.Paper == 0 {
+		paper, err := defaultPaper(input.CourseID, input.SessionID, input.Type)
+		if err != nil {
+			return nil, "", false, err
+		}
+		input.Paper = paper
+	}
+
+	// Generate question ID; approved papers are closed, so refuse before any image lands in their folder
+	questionID := generateQuestionID(input.CourseID, input.SessionID, input.Type, input.Paper)
+	if err := ensurePaperOpen(questionID, input.CourseID, input.SessionID, input.Type); err != nil {
+		return nil, "", false, err
+	}
+
 	// Process upload results and get temp public IDs
 	tempPublicIDs, err := processUploadResults(input.UploadResults)
 	if err != nil {
 		return nil, "", false, err
 	}
 
-	// Generate question ID and process images
-	questionID := generateQuestionID(input.CourseID, input.SessionID, input.Type)
-	finalImageURLs, processingStatus := processQuestionImages(tempPublicIDs, questionID)
+	// Process images
+	finalImages, processingStatus := processQuestionImages(tempPublicIDs, questionID)
 
 	// Create or update the question
-	question, message, created, err := createOrUpdateQuestion(questionID, input, finalImageURLs, processingStatus, userID)
+	question, message, created, err := createOrUpdateQuestion(questionID, input, finalImages, processingStatus, userID)
 	if err != nil {
 		return nil, "", false, err
 	}
@@ -107,6 +279,71 @@ func validateCourseAndSession(courseID, sessionID string) error {
 	return nil
 }
 
+// defaultPaper picks the paper for an upload that didn't name one. That is paper 1 while the course,
+// session and type have no papers yet; otherwise the uploader has to say whether they are adding pages
+// to an existing paper or uploading another one, rather than being merged into paper 1.
+func defaultPaper(courseID, sessionID string, questionType models.QuestionType) (int, error) {
+	papers, err := existingPapers(courseID, sessionID, questionType)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(papers) == 0 {
+		return 1, nil
+	}
+
+	return 0, &models.BusinessError{
+		Code:    409,
+		Message: fmt.Sprintf("Papers %s already exist for this course, session and type; set paper to add pages to one of them or to %d for a new paper", joinInts(papers), papers[len(papers)-1]+1),
+	}
+}
+
+// ensurePaperOpen refuses uploads to a paper that is already approved, suggesting the next free paper instead
+func ensurePaperOpen(questionID, courseID, sessionID string, questionType models.QuestionType) error {
+	var approved int64
+	if err := db.Model(&models.Question{}).Where("id = ? AND approved = ?", questionID, true).Count(&approved).Error; err != nil {
+		return errS.Db(err)
+	}
+	if approved == 0 {
+		return nil
+	}
+
+	papers, err := existingPapers(courseID, sessionID, questionType)
+	if err != nil {
+		return err
+	}
+
+	next := 1
+	if len(papers) > 0 {
+		next = papers[len(papers)-1] + 1
+	}
+
+	return &models.BusinessError{
+		Code:    409,
+		Message: fmt.Sprintf("This paper is already approved; set paper to %d to upload a new paper", next),
+	}
+}
+
+// existingPapers lists the paper numbers already used by the course, session and type, in order
+func existingPapers(courseID, sessionID string, questionType models.QuestionType) ([]int, error) {
+	var papers []int
+	if err := db.Model(&models.Question{}).
+		Where("course_id = ? AND session_id = ? AND type = ?", courseID, sessionID, questionType).
+		Order("paper ASC").Pluck("paper", &papers).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+	return papers, nil
+}
+
+// joinInts lists numbers for messages, e.g. "1, 2"
+func joinInts(numbers []int) string {
+	parts := make([]string, len(numbers))
+	for i, number := range numbers {
+		parts[i] = strconv.Itoa(number)
+	}
+	return strings.Join(parts, ", ")
+}
+
 // processUploadResults extracts and validates upload results
 func processUploadResults(uploadResults *models.UploadResponse) ([]string, error) {
 	var tempPublicIDs []string
@@ -132,15 +369,15 @@ func processUploadResults(uploadResults *models.UploadResponse) ([]string, error
 }
 
 // createOrUpdateQuestion creates a new question or updates an existing unapproved one
-func createOrUpdateQuestion(questionID string, input models.CreateQuestionDTO, finalImageURLs []string, processingStatus, userID string) (*models.Question, string, bool, error) {
+func createOrUpdateQuestion(questionID string, input models.CreateQuestionDTO, finalImages []models.QuestionImage, processingStatus, userID string) (*models.Question, string, bool, error) {
 	var question models.Question
 	
 	// Check if question exists and is not approved
-	dbResult := db.Where("id = ? AND approved = ?", questionID, false).First(&question)
+	dbResult := db.Preload("Images", orderedImages).Where("id = ? AND approved = ?", questionID, false).First(&question)
 
 	if dbResult.Error == nil {
 		// Update existing unapproved question
-		return updateExistingQuestion(&question, finalImageURLs)
+		return updateExistingQuestion(&question, finalImages, userID)
 	}
 
 	if dbResult.Error != gorm.ErrRecordNotFound {
@@ -148,53 +385,109 @@ func createOrUpdateQuestion(questionID string, input models.CreateQuestionDTO, f
 	}
 
 	// Create new question
-	return createNewQuestion(questionID, input, finalImageURLs, processingStatus, userID)
+	return createNewQuestion(questionID, input, finalImages, processingStatus, userID)
 }
 
 // updateExistingQuestion appends images to an existing unapproved question
-func updateExistingQuestion(question *models.Question, finalImageURLs []string) (*models.Question, string, bool, error) {
-	question.ImageLinks = append(question.ImageLinks, finalImageURLs...)
-	if err := db.Save(question).Error; err != nil {
+func updateExistingQuestion(question *models.Question, finalImages []models.QuestionImage, userID string) (*models.Question, string, bool, error) {
+	before := *question
+
+	// New pages continue after the existing ones
+	for i := range finalImages {
+		finalImages[i].QuestionID = question.ID
+		finalImages[i].Page = len(question.Images) + i + 1
+	}
+
+	// New pages on a rejected question send it back to the moderation queue, without the old verdict
+	if question.ModerationStatus == models.ModerationStatusRejected {
+		question.ModerationStatus = models.ModerationStatusPending
+		question.ModerationReason = nil
+		question.ModeratedAt = nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if len(finalImages) > 0 {
+			if err := tx.Create(&finalImages).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Images").Save(question).Error; err != nil {
+			return err
+		}
+		return recordModerationEvent(tx, models.ModerationActionResubmit, &before, question, userID, nil)
+	})
+	if err != nil {
 		return nil, "", false, errS.Db(err)
 	}
+
+	question.Images = append(question.Images, finalImages...)
+	question.SyncImageLinks()
+	InvalidatePrintCache(question.ID)
 	return question, "Images appended to existing unapproved question successfully", false, nil
 }
 
 // createNewQuestion creates a new question
-func createNewQuestion(questionID string, input models.CreateQuestionDTO, finalImageURLs []string, processingStatus, userID string) (*models.Question, string, bool, error) {
+func createNewQuestion(questionID string, input models.CreateQuestionDTO, finalImages []models.QuestionImage, processingStatus, userID string) (*models.Question, string, bool, error) {
+	for i := range finalImages {
+		finalImages[i].Page = i + 1
+	}
+
 	question := models.Question{
 		ID:               questionID,
 		CourseID:         input.CourseID,
 		SessionID:        input.SessionID,
 		Type:             input.Type,
+		Paper:            input.Paper,
+		PaperLabel:       input.PaperLabel,
 		Lecturer:         input.Lecturer,
 		TimeAllowed:      input.TimeAllowed,
 		DocLink:          input.DocLink,
 		Tips:             input.Tips,
 		Approved:         false,
+		ModerationStatus: models.ModerationStatusPending,
 		Downloads:        new(int),
 		Views:            new(int),
-		ImageLinks:       finalImageURLs,
+		Images:           finalImages,
 		ProcessingStatus: &processingStatus,
 		UploaderID:       &userID,
 	}
 
-	if err := db.Create(&question).Error; err != nil {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&question).Error; err != nil {
+			return err
+		}
+		return recordModerationEvent(tx, models.ModerationActionSubmit, &models.Question{}, &question, userID, nil)
+	})
+	if err != nil {
 		return nil, "", false, errS.Db(err)
 	}
 
+	question.SyncImageLinks()
+
 	return &question, "Question created successfully and is pending approval", true, nil
 }
 
-// processQuestionImages handles concurrent image processing with error resilience
-func processQuestionImages(tempPublicIDs []string, questionID string) ([]string, string) {
+// processQuestionImages moves staged uploads into the question's folder
+func processQuestionImages(tempPublicIDs []string, questionID string) ([]models.QuestionImage, string) {
+	assets, status := promoteStagedImages(tempPublicIDs, questionFolder(questionID))
+
+	images := make([]models.QuestionImage, len(assets))
+	for i, asset := range assets {
+		images[i] = models.QuestionImage{QuestionID: questionID, ImageAsset: asset}
+	}
+
+	return images, status
+}
+
+// promoteStagedImages handles concurrent image processing with error resilience
+func promoteStagedImages(tempPublicIDs []string, folder string) ([]models.ImageAsset, string) {
 	if len(tempPublicIDs) == 0 {
-		return []string{}, "processed"
+		return []models.ImageAsset{}, "processed"
 	}
 	const maxConcurrentMoves = 5
 	semaphore := make(chan struct{}, maxConcurrentMoves)
 	
-	results := make([]string, len(tempPublicIDs))
+	results := make([]*models.ImageAsset, len(tempPublicIDs))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var successCount int
@@ -210,14 +503,14 @@ func processQuestionImages(tempPublicIDs []string, questionID string) ([]string,
 			defer func() { <-semaphore }()
 
 			// Move file to permanent location
-			finalURL, err := MoveFileToPermanent(tempPublicID, questionID)
+			finalImage, err := MoveFileToPermanent(tempPublicID, folder)
 			
 			mu.Lock()
 			if err != nil {
-				fmt.Printf("Error moving image %s to permanent location: %v\n", tempPublicID, err)
-				results[index] = "" // Mark as failed
+				log.Printf("Error moving image %s to permanent location: %v", tempPublicID, err)
+				results[index] = nil // Mark as failed
 			} else {
-				results[index] = finalURL
+				results[index] = finalImage
 				successCount++
 			}
 			mu.Unlock()
@@ -227,11 +520,11 @@ func processQuestionImages(tempPublicIDs []string, questionID string) ([]string,
 	// Wait for all moves to complete
 	wg.Wait()
 
-	// Filter out failed moves
-	var finalURLs []string
-	for _, url := range results {
-		if url != "" {
-			finalURLs = append(finalURLs, url)
+	// Filter out failed moves, keeping upload order
+	var finalImages []models.ImageAsset
+	for _, image := range results {
+		if image != nil {
+			finalImages = append(finalImages, *image)
 		}
 	}
 
@@ -244,19 +537,264 @@ func processQuestionImages(tempPublicIDs []string, questionID string) ([]string,
 		status = "partial"
 	}
 
-	return finalURLs, status
+	return finalImages, status
 }
 
-// generateQuestionID generates an ID for the question based on course, session, and type
-func generateQuestionID(courseID string, sessionID string, questionType models.QuestionType) string {
+// orderedImages preloads a question's pages in page order
+func orderedImages(tx *gorm.DB) *gorm.DB {
+	return tx.Order("page ASC, id ASC")
+}
+
+// UpdateQuestion applies a metadata patch to a question.
+// Uploaders may edit their own questions while they are pending; admins may edit any question.
+// Changing the course, session or type regenerates the ID and moves the images to the matching folder.
+func UpdateQuestion(id, userID, role string, input models.UpdateQuestionDTO) (*models.Question, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	question, err := getManageableQuestion(id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	courseID, sessionID, questionType, paper := question.CourseID, question.SessionID, question.Type, question.Paper
+	if input.CourseID != nil {
+		courseID = strings.ToUpper(*input.CourseID)
+	}
+	if input.SessionID != nil {
+		sessionID = *input.SessionID
+	}
+	if input.Type != nil {
+		questionType = *input.Type
+	}
+	if input.Paper != nil {
+		paper = *input.Paper
+	}
+
+	if courseID != question.CourseID || sessionID != question.SessionID {
+		if err := validateCourseAndSession(courseID, sessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := map[string]interface{}{
+		"course_id":  courseID,
+		"session_id": sessionID,
+		"type":       questionType,
+		"paper":      paper,
+	}
+	if input.PaperLabel != nil {
+		updates["paper_label"] = *input.PaperLabel
+	}
+	if input.Lecturer != nil {
+		updates["lecturer"] = *input.Lecturer
+	}
+	if input.TimeAllowed != nil {
+		updates["time_allowed"] = *input.TimeAllowed
+	}
+	if input.DocLink != nil {
+		updates["doc_link"] = *input.DocLink
+	}
+	if input.Tips != nil {
+		updates["tips"] = *input.Tips
+	}
+
+	newID := generateQuestionID(courseID, sessionID, questionType, paper)
+	if newID != question.ID {
+		var count int64
+		if err := db.Model(&models.Question{}).Where("id = ?", newID).Count(&count).Error; err != nil {
+			return nil, errS.Db(err)
+		}
+		if count > 0 {
+			return nil, &models.BusinessError{Code: 409, Message: "A question already exists for this course, session, type and paper", Details: newID}
+		}
+	}
+
+	if err := applyQuestionUpdate(question, newID, updates); err != nil {
+		return nil, err
+	}
+
+	var updated models.Question
+	if err := db.Preload("Course").Preload("Session").Preload("Images", orderedImages).Where("id = ?", newID).First(&updated).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+
+	return &updated, nil
+}
+
+// applyQuestionUpdate writes the column updates and, when newID differs, renames the question.
+// Images are moved to the new folder first and moved back if the database update fails.
+func applyQuestionUpdate(question *models.Question, newID string, updates map[string]interface{}) error {
+	if newID != question.ID {
+		if err := MoveQuestionAssets(question.ID, newID, question.Images); err != nil {
+			return models.NewUploadError(err.Error())
+		}
+		updates["id"] = newID
+	}
+
+	// Related rows follow the ID change through ON UPDATE CASCADE
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Question{}).Where("id = ?", question.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if newID == question.ID {
+			return nil
+		}
+		for _, image := range question.Images {
+			if err := tx.Model(&image).Updates(map[string]interface{}{"public_id": image.PublicID, "url": image.URL}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if newID != question.ID {
+			if undoErr := MoveQuestionAssets(newID, question.ID, question.Images); undoErr != nil {
+				log.Printf("Warning: Failed to move images back to %s: %v", question.ID, undoErr)
+			}
+		}
+		return errS.Db(err)
+	}
+	InvalidatePrintCache(question.ID)
+
+	return nil
+}
+
+// DeleteQuestion removes a question and its images.
+// Uploaders may delete their own questions while they are pending; admins may delete any question.
+func DeleteQuestion(id, userID, role string) error {
+	question, err := getManageableQuestion(id, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return deleteQuestionsWithAssets([]string{question.ID}, nil)
+}
+
+// deleteQuestionsWithAssets destroys the images of every listed question, then deletes the rows.
+// Images go first so a storage failure leaves the rows in place and the delete can be retried.
+// The optional then callback runs in the same transaction, after the questions are gone.
+func deleteQuestionsWithAssets(questionIDs []string, then func(tx *gorm.DB) error) error {
+	// Solution pages live in their own folders, so they have to be found and destroyed separately
+	var solutionIDs []string
+	if len(questionIDs) > 0 {
+		if err := db.Model(&models.Solution{}).Where("question_id IN ?", questionIDs).Pluck("id", &solutionIDs).Error; err != nil {
+			return errS.Db(err)
+		}
+	}
+	for _, solutionID := range solutionIDs {
+		if err := DeleteSolutionAssets(solutionID); err != nil {
+			return models.NewNetworkError(err.Error())
+		}
+	}
+
+	for _, questionID := range questionIDs {
+		if err := DeleteQuestionAssets(questionID); err != nil {
+			return models.NewNetworkError(err.Error())
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if len(questionIDs) > 0 {
+			if err := tx.Where("id IN ?", questionIDs).Delete(&models.Question{}).Error; err != nil {
+				return err
+			}
+		}
+		if then != nil {
+			return then(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		if businessErr, ok := err.(*models.BusinessError); ok {
+			return businessErr
+		}
+		return errS.Db(err)
+	}
+
+	for _, questionID := range questionIDs {
+		InvalidatePrintCache(questionID)
+	}
+
+	return nil
+}
+
+// getManageableQuestion loads a question the user is allowed to change.
+// Uploaders may change their own questions while they are pending; admins may change any question.
+func getManageableQuestion(id, userID, role string) (*models.Question, error) {
+	var question models.Question
+	if err := db.Preload("Images", orderedImages).Where("id = ?", id).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+
+	if !canManageQuestion(&question, userID, role) {
+		return nil, models.ErrForbidden
+	}
+	if role != string(models.RoleAdmin) && question.ModerationStatus != models.ModerationStatusPending {
+		return nil, &models.BusinessError{Code: 403, Message: "Only pending questions can be changed by their uploader"}
+	}
+
+	return &question, nil
+}
+
+// visibleTo limits a question query to what the user may read: everyone sees approved questions,
+// uploaders also see their own pending or rejected ones, and admins see everything
+func visibleTo(userID, role string) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if role == string(models.RoleAdmin) {
+			return tx
+		}
+		if userID == "" {
+			return tx.Where("questions.approved = ?", true)
+		}
+		return tx.Where("(questions.approved = ? OR questions.uploader_id = ?)", true, userID)
+	}
+}
+
+// getVisibleQuestionID checks that the user can see the question and returns its ID
+func getVisibleQuestionID(questionID, userID, role string) (string, error) {
+	var question models.Question
+	if err := db.Scopes(visibleTo(userID, role)).Select("questions.id").
+		Where("questions.id = ?", questionID).First(&question).Error; err != nil {
+		return "", errS.Db(err, "Question")
+	}
+	return question.ID, nil
+}
+
+// canManageQuestion reports whether the user uploaded the question or is an admin
+func canManageQuestion(question *models.Question, userID, role string) bool {
+	if role == string(models.RoleAdmin) {
+		return true
+	}
+	return userID != "" && question.UploaderID != nil && *question.UploaderID == userID
+}
+
+// questionTypeCodes are the short type codes used in question IDs. TEST and EXAM keep the
+// initials they have always had; the rest get two letters so no two types can share a code.
+var questionTypeCodes = map[models.QuestionType]string{
+	models.QuestionTypeTest:       "t",
+	models.QuestionTypeExam:       "e",
+	models.QuestionTypeResit:      "rs",
+	models.QuestionTypeQuiz:       "qz",
+	models.QuestionTypeAssignment: "as",
+	models.QuestionTypePractical:  "pr",
+	models.QuestionTypeMockExam:   "mx",
+}
+
+// generateQuestionID generates an ID for the question based on course, session, type and paper
+func generateQuestionID(courseID string, sessionID string, questionType models.QuestionType, paper int) string {
 	// Convert courseID to lowercase for the ID
 	lowerCourseID := strings.ToLower(courseID)
 
-	// Get the first letter of the question type, converted to lowercase
-	typeInitial := strings.ToLower(string(questionType[0]))
+	// Unknown types fall back to their lowercased name rather than risking a shared code
+	typeCode, ok := questionTypeCodes[questionType]
+	if !ok {
+		typeCode = strings.ToLower(string(questionType))
+	}
 
-	// Format: courseId-sessionId-typeInitial
-	return fmt.Sprintf("%s-%s-%s", lowerCourseID, sessionID, typeInitial)
+	// Format: courseId-sessionId-typeCodePaper
+	return fmt.Sprintf("%s-%s-%s%d", lowerCourseID, sessionID, typeCode, paper)
 }
 
 // BuildQuestionResponse builds the response for question creation
@@ -267,7 +805,7 @@ func BuildQuestionResponse(question *models.Question, finalImageURLs, tempPublic
 		if !created {
 			action = "updated"
 		}
-		fmt.Printf("Successfully %s question %s with %d images, status: %s\n", action, question.ID, len(finalImageURLs), processingStatus)
+		log.Printf("Successfully %s question %s with %d images, status: %s", action, question.ID, len(finalImageURLs), processingStatus)
 	}
 
 	// Prepare response
@@ -276,8 +814,11 @@ func BuildQuestionResponse(question *models.Question, finalImageURLs, tempPublic
 		CourseID:         question.CourseID,
 		SessionID:        question.SessionID,
 		Type:             question.Type,
+		Paper:            question.Paper,
+		PaperLabel:       question.PaperLabel,
 		ImageCount:       len(question.ImageLinks),
 		ImageLinks:       question.ImageLinks,
+		Images:           question.Images,
 		ProcessingStatus: processingStatus,
 		Approved:         question.Approved,
 		CreatedAt:        question.CreatedAt.Format(time.RFC3339),
@@ -304,3 +845,22 @@ func GetIntQuery(value string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// GetPageQuery parses page and limit query values, applying defaults and the page size cap
+func GetPageQuery(pageValue, limitValue string) (int, int) {
+	return clampPage(GetIntQuery(pageValue, 1), GetIntQuery(limitValue, DefaultPageSize))
+}
+
+// clampPage keeps page at least 1 and limit between 1 and MaxPageSize
+func clampPage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	return page, limit
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"qb/pkg/models"
+	"testing"
+)
+
+func TestGenerateQuestionID(t *testing.T) {
+	tests := []struct {
+		name         string
+		courseID     string
+		sessionID    string
+		questionType models.QuestionType
+		paper        int
+		want         string
+	}{
+		{"test keeps its initial", "CSC201", "2023", models.QuestionTypeTest, 1, "csc201-2023-t1"},
+		{"exam keeps its initial", "CSC201", "2023", models.QuestionTypeExam, 1, "csc201-2023-e1"},
+		{"resit", "CSC201", "2023", models.QuestionTypeResit, 1, "csc201-2023-rs1"},
+		{"quiz", "MTH101", "2022", models.QuestionTypeQuiz, 3, "mth101-2022-qz3"},
+		{"assignment", "MTH101", "2022", models.QuestionTypeAssignment, 2, "mth101-2022-as2"},
+		{"practical", "PHY107", "2021", models.QuestionTypePractical, 1, "phy107-2021-pr1"},
+		{"mock exam", "PHY107", "2021", models.QuestionTypeMockExam, 1, "phy107-2021-mx1"},
+		{"later paper", "CSC201", "2023", models.QuestionTypeTest, 12, "csc201-2023-t12"},
+		{"session ID is kept as is", "csc201", "ABC", models.QuestionTypeTest, 1, "csc201-ABC-t1"},
+		{"unknown type falls back to its name", "CSC201", "2023", models.QuestionType("ORAL"), 1, "csc201-2023-oral1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generateQuestionID(tt.courseID, tt.sessionID, tt.questionType, tt.paper); got != tt.want {
+				t.Fatalf("generateQuestionID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuestionTypeCodesAreUnique(t *testing.T) {
+	types := []models.QuestionType{
+		models.QuestionTypeTest,
+		models.QuestionTypeExam,
+		models.QuestionTypeResit,
+		models.QuestionTypeQuiz,
+		models.QuestionTypeAssignment,
+		models.QuestionTypePractical,
+		models.QuestionTypeMockExam,
+	}
+
+	seen := make(map[string]models.QuestionType)
+	for _, questionType := range types {
+		code, ok := questionTypeCodes[questionType]
+		if !ok {
+			t.Fatalf("%s has no type code", questionType)
+		}
+		if other, dup := seen[code]; dup {
+			t.Fatalf("%s and %s share the code %q", other, questionType, code)
+		}
+		seen[code] = questionType
+	}
+
+	// Codes must be letters only, so where the code ends and the paper number starts is never ambiguous
+	for code := range seen {
+		for _, r := range code {
+			if r < 'a' || r > 'z' {
+				t.Fatalf("code %q has a character other than a lowercase letter", code)
+			}
+		}
+	}
+}
+
+func TestJoinInts(t *testing.T) {
+	tests := []struct {
+		numbers []int
+		want    string
+	}{
+		{nil, ""},
+		{[]int{1}, "1"},
+		{[]int{1, 2, 10}, "1, 2, 10"},
+	}
+
+	for _, tt := range tests {
+		if got := joinInts(tt.numbers); got != tt.want {
+			t.Fatalf("joinInts(%v) = %q, want %q", tt.numbers, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"qb/pkg/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateQuestion creates or changes the user's rating of an approved question.
+// Fields left out keep the value from the user's previous rating.
+func RateQuestion(questionID, userID string, input models.RateQuestionDTO) (*models.RatingResponse, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	var question models.Question
+	if err := db.Select("id", "approved").Where("id = ?", questionID).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+	if !question.Approved {
+		return nil, &models.BusinessError{Code: 403, Message: "Only approved questions can be rated"}
+	}
+
+	rating := models.QuestionRating{UserID: userID, QuestionID: question.ID}
+	existing, err := getMyRating(question.ID, userID)
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+	if existing != nil {
+		rating = *existing
+	}
+	if input.Difficulty != nil {
+		rating.Difficulty = input.Difficulty
+	}
+	if input.Readable != nil {
+		rating.Readable = input.Readable
+	}
+	if input.Complete != nil {
+		rating.Complete = input.Complete
+	}
+	if rating.Difficulty == nil && rating.Readable == nil && rating.Complete == nil {
+		return nil, errS.Invalid("Rate the difficulty or vote on readable/complete")
+	}
+
+	var summary models.RatingSummary
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&rating).Error; err != nil {
+			return err
+		}
+		return refreshRatingSummary(tx, question.ID, &summary)
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &models.RatingResponse{Rating: &rating, Summary: summary}, nil
+}
+
+// RemoveRating withdraws the user's rating of a question; removing a missing rating is a no-op
+func RemoveRating(questionID, userID string) (*models.RatingResponse, error) {
+	var summary models.RatingSummary
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND question_id = ?", userID, questionID).Delete(&models.QuestionRating{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Model(&models.Question{}).Where("id = ?", questionID).Scan(&summary).Error
+		}
+		return refreshRatingSummary(tx, questionID, &summary)
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &models.RatingResponse{Summary: summary}, nil
+}
+
+// getMyRating loads the user's rating of a question, nil if they haven't rated it
+func getMyRating(questionID, userID string) (*models.QuestionRating, error) {
+	var ratings []models.QuestionRating
+	if err := db.Where("user_id = ? AND question_id = ?", userID, questionID).Limit(1).Find(&ratings).Error; err != nil {
+		return nil, err
+	}
+	if len(ratings) == 0 {
+		return nil, nil
+	}
+	return &ratings[0], nil
+}
+
+// refreshRatingSummary recomputes a question's rating aggregates from its ratings and reads them back.
+// Recomputing rather than adjusting keeps the aggregates right when a user changes their rating.
+func refreshRatingSummary(tx *gorm.DB, questionID string, summary *models.RatingSummary) error {
+	ratings := "FROM question_ratings WHERE question_id = ?"
+	updates := map[string]interface{}{
+		"difficulty":       gorm.Expr("(SELECT ROUND(AVG(difficulty), 2) "+ratings+")", questionID),
+		"difficulty_votes": gorm.Expr("(SELECT COUNT(difficulty) "+ratings+")", questionID),
+		"readable_yes":     gorm.Expr("(SELECT COUNT(*) "+ratings+" AND readable = TRUE)", questionID),
+		"readable_no":      gorm.Expr("(SELECT COUNT(*) "+ratings+" AND readable = FALSE)", questionID),
+		"complete_yes":     gorm.Expr("(SELECT COUNT(*) "+ratings+" AND complete = TRUE)", questionID),
+		"complete_no":      gorm.Expr("(SELECT COUNT(*) "+ratings+" AND complete = FALSE)", questionID),
+	}
+	// UpdateColumns so a rating doesn't count as an edit of the question
+	if err := tx.Model(&models.Question{}).Where("id = ?", questionID).UpdateColumns(updates).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Question{}).Where("id = ?", questionID).Scan(summary).Error
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reportHideThreshold is how many users must have open reports on a question before it is hidden
+var reportHideThreshold = 5
+
+// InitReportThreshold loads the auto-hide threshold from REPORT_HIDE_THRESHOLD
+func InitReportThreshold() {
+	threshold, err := strconv.Atoi(utils.GetEnv("REPORT_HIDE_THRESHOLD", "5"))
+	if err != nil || threshold < 1 {
+		log.Printf("Warning: Invalid REPORT_HIDE_THRESHOLD, falling back to 5")
+		threshold = 5
+	}
+	reportHideThreshold = threshold
+}
+
+// CreateReport files the user's report against an approved question.
+// Once enough different users have open reports on it, the question is hidden and sent back to moderation.
+func CreateReport(questionID, userID string, input models.CreateReportDTO) (*models.Report, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	var details *string
+	if input.Details != nil {
+		if trimmed := strings.TrimSpace(*input.Details); trimmed != "" {
+			details = &trimmed
+		}
+	}
+	if input.Reason == models.ReportReasonOther && details == nil {
+		return nil, errS.Invalid("Details are required when the reason is OTHER")
+	}
+
+	var question models.Question
+	if err := db.Select("id", "approved").Where("id = ?", questionID).First(&question).Error; err != nil {
+		return nil, errS.Db(err, "Question")
+	}
+	if !question.Approved {
+		return nil, &models.BusinessError{Code: 403, Message: "Only approved questions can be reported"}
+	}
+
+	var open int64
+	if err := db.Model(&models.Report{}).Where("question_id = ? AND reporter_id = ? AND status = ?",
+		question.ID, userID, models.ReportStatusOpen).Count(&open).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+	if open > 0 {
+		return nil, &models.BusinessError{Code: 409, Message: "You have already reported this question"}
+	}
+
+	report := models.Report{
+		QuestionID: question.ID,
+		ReporterID: &userID,
+		Reason:     input.Reason,
+		Details:    details,
+		Status:     models.ReportStatusOpen,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+		return hideIfReported(tx, question.ID)
+	})
+	if err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &report, nil
+}
+
+// GetReports lists reports in the given status, oldest first, optionally for a single question
+func GetReports(status, questionID string, page, limit int) ([]models.Report, error) {
+	if status == "" {
+		status = string(models.ReportStatusOpen)
+	}
+	switch models.ReportStatus(status) {
+	case models.ReportStatusOpen, models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		return nil, errS.Invalid("status must be one of OPEN, RESOLVED or DISMISSED")
+	}
+
+	query := db.Preload("Question").Preload("Reporter", publicAuthor).Preload("Reviewer", publicAuthor).
+		Where("status = ?", status)
+
+	if questionID != "" {
+		query = query.Where("question_id = ?", questionID)
+	}
+
+	var reports []models.Report
+
+	offset := (page - 1) * limit
+
+	if err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&reports).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return reports, nil
+}
+
+// ResolveReport closes a report that led to a fix
+func ResolveReport(id uint, adminID string, input models.ReviewReportDTO) (*models.Report, error) {
+	return reviewReport(id, adminID, models.ReportStatusResolved, input)
+}
+
+// DismissReport closes a report that needed no action
+func DismissReport(id uint, adminID string, input models.ReviewReportDTO) (*models.Report, error) {
+	return reviewReport(id, adminID, models.ReportStatusDismissed, input)
+}
+
+// reviewReport closes an open report with the given status.
+// Restoring a hidden question is left to the usual approval, so the admin looks at it first.
+func reviewReport(id uint, adminID string, status models.ReportStatus, input models.ReviewReportDTO) (*models.Report, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	var report models.Report
+	if err := db.Where("id = ?", id).First(&report).Error; err != nil {
+		return nil, errS.Db(err, "Report")
+	}
+
+	if report.Status != models.ReportStatusOpen {
+		return nil, errS.Invalid("Report is already " + strings.ToLower(string(report.Status)))
+	}
+
+	var note *string
+	if trimmed := strings.TrimSpace(input.Note); trimmed != "" {
+		note = &trimmed
+	}
+	now := time.Now()
+
+	report.Status = status
+	report.ReviewerID = &adminID
+	report.ReviewNote = note
+	report.ReviewedAt = &now
+
+	updates := map[string]interface{}{
+		"status":      status,
+		"reviewer_id": adminID,
+		"review_note": note,
+		"reviewed_at": now,
+	}
+	if err := db.Model(&report).Updates(updates).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &report, nil
+}
+
+// hideIfReported hides an approved question once enough different users have open reports on it.
+// Only reports filed since the question was last moderated count, so re-approving a question gives it
+// a fresh start even while older reports are still open.
+// The question row is locked so concurrent reports hide it, and log the hide, only once.
+func hideIfReported(tx *gorm.DB, questionID string) error {
+	var question models.Question
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", questionID).First(&question).Error; err != nil {
+		return err
+	}
+	if !question.Approved {
+		return nil
+	}
+
+	query := tx.Model(&models.Report{}).Where("question_id = ? AND status = ?", questionID, models.ReportStatusOpen)
+	if question.ModeratedAt != nil {
+		query = query.Where("created_at > ?", *question.ModeratedAt)
+	}
+
+	var reporters int64
+	if err := query.Distinct("reporter_id").Count(&reporters).Error; err != nil {
+		return err
+	}
+	if reporters < int64(reportHideThreshold) {
+		return nil
+	}
+
+	reason := fmt.Sprintf("Hidden automatically after reports from %d users", reporters)
+	now := time.Now()
+
+	before := question
+	question.Approved = false
+	question.ModerationStatus = models.ModerationStatusPending
+	question.ModerationReason = &reason
+	question.ModeratedAt = &now
+
+	updates := map[string]interface{}{
+		"approved":          false,
+		"moderation_status": models.ModerationStatusPending,
+		"moderation_reason": reason,
+		"moderated_at":      now,
+	}
+	if err := tx.Model(&question).Updates(updates).Error; err != nil {
+		return err
+	}
+	return recordModerationEvent(tx, models.ModerationActionHide, &before, &question, "", &reason)
+}
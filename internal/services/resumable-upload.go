@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// ResumableMaxSize is the largest file the tus endpoint accepts, the same limit as ValidateImageFile
+	ResumableMaxSize = 10 * 1024 * 1024
+
+	// maxFilesPerRequest matches the file limit of a single /upload-images request
+	maxFilesPerRequest = 5
+)
+
+var (
+	resumableDir   string
+	resumableLocks sync.Map // upload ID -> *sync.Mutex, so one upload receives one PATCH at a time
+)
+
+// InitResumableUploads prepares the directory that holds partial tus uploads and starts their cleanup
+func InitResumableUploads() {
+	resumableDir = utils.GetEnv("TUS_UPLOAD_DIR", filepath.Join(os.TempDir(), "qb_tus"))
+	if err := os.MkdirAll(resumableDir, 0o755); err != nil {
+		log.Printf("Warning: Failed to create resumable upload directory %s: %v", resumableDir, err)
+	}
+
+	go startResumableCleanup()
+}
+
+// CreateResumableUpload registers a file the user is about to send in chunks.
+// Without a request ID a new staged-upload request is started; with one, the file joins the user's earlier files.
+func CreateResumableUpload(userID string, length int64, filename, requestID string) (*models.ResumableUpload, error) {
+	if length <= 0 {
+		return nil, errS.Invalid("Upload-Length must be a positive number of bytes")
+	}
+	if length > ResumableMaxSize {
+		return nil, &models.BusinessError{Code: 413, Message: "File size exceeds 10MB limit"}
+	}
+
+	filename = strings.TrimSpace(filename)
+	if len(filename) > 255 {
+		filename = filename[:255]
+	}
+
+	upload := models.ResumableUpload{
+		ID:         uuid.New().String(),
+		RequestID:  requestID,
+		UploaderID: userID,
+		Filename:   filename,
+		Position:   1,
+		Length:     length,
+		ExpiresAt:  time.Now().Add(tempUploadTTL),
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if upload.RequestID == "" {
+			upload.RequestID = GenerateRequestID()
+			if err := tx.Create(&models.TemporaryUpload{RequestID: upload.RequestID, ExpiresAt: upload.ExpiresAt}).Error; err != nil {
+				return err
+			}
+			return tx.Create(&upload).Error
+		}
+
+		// Locking the request serialises files joining it, so each gets its own position and the limit holds
+		var request models.TemporaryUpload
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("request_id = ? AND expires_at > ?", upload.RequestID, time.Now()).
+			First(&request).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return &models.BusinessError{Code: 404, Message: "Upload request not found, expired or already used"}
+			}
+			return err
+		}
+
+		var uploads []models.ResumableUpload
+		if err := tx.Select("uploader_id").Where("request_id = ?", upload.RequestID).Find(&uploads).Error; err != nil {
+			return err
+		}
+		if len(uploads) == 0 {
+			// Requests started by /upload-images can't be continued here
+			return &models.BusinessError{Code: 404, Message: "Upload request not found, expired or already used"}
+		}
+		for _, existing := range uploads {
+			if existing.UploaderID != userID {
+				return models.ErrForbidden
+			}
+		}
+		if len(uploads) >= maxFilesPerRequest {
+			return errS.Invalid(fmt.Sprintf("Maximum %d files allowed per request", maxFilesPerRequest))
+		}
+
+		upload.Position = len(uploads) + 1
+		return tx.Create(&upload).Error
+	})
+	if err != nil {
+		if businessErr, ok := err.(*models.BusinessError); ok {
+			return nil, businessErr
+		}
+		return nil, errS.Db(err)
+	}
+
+	file, err := os.Create(resumablePath(upload.ID))
+	if err != nil {
+		db.Delete(&upload)
+		return nil, fmt.Errorf("failed to create upload file: %w", err)
+	}
+	file.Close()
+
+	return &upload, nil
+}
+
+// GetResumableUpload returns the user's unexpired upload, for clients checking how much arrived
+func GetResumableUpload(id, userID string) (*models.ResumableUpload, error) {
+	var upload models.ResumableUpload
+	if err := db.Where("id = ? AND uploader_id = ? AND expires_at > ?", id, userID, time.Now()).First(&upload).Error; err != nil {
+		return nil, errS.Db(err, "Upload")
+	}
+	return &upload, nil
+}
+
+// AppendResumableUpload writes the next chunk of an upload at the given offset.
+// Once the last byte arrives the file is staged like an /upload-images file; if staging fails,
+// sending an empty chunk at the final offset tries again.
+func AppendResumableUpload(id, userID string, offset int64, body io.Reader) (*models.ResumableUpload, error) {
+	lock, _ := resumableLocks.LoadOrStore(id, &sync.Mutex{})
+	if !lock.(*sync.Mutex).TryLock() {
+		return nil, &models.BusinessError{Code: 423, Message: "Upload is already receiving data"}
+	}
+	defer lock.(*sync.Mutex).Unlock()
+
+	upload, err := GetResumableUpload(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAppendOffset(upload, offset); err != nil {
+		return nil, err
+	}
+
+	if upload.Offset < upload.Length {
+		written, writeErr := appendChunk(upload, body)
+		if written > 0 {
+			upload.Offset += written
+			if err := db.Model(upload).Update("offset", upload.Offset).Error; err != nil {
+				return nil, errS.Db(err)
+			}
+		}
+		if writeErr != nil {
+			return nil, models.NewNetworkError(fmt.Sprintf("Upload interrupted at byte %d: %v", upload.Offset, writeErr))
+		}
+	}
+
+	if upload.Offset == upload.Length {
+		if err := finishResumableUpload(upload); err != nil {
+			return nil, err
+		}
+	}
+
+	return upload, nil
+}
+
+// DeleteResumableUpload cancels an upload; a finished file is also taken out of its request
+func DeleteResumableUpload(id, userID string) error {
+	lock, _ := resumableLocks.LoadOrStore(id, &sync.Mutex{})
+	if !lock.(*sync.Mutex).TryLock() {
+		return &models.BusinessError{Code: 423, Message: "Upload is already receiving data"}
+	}
+	defer lock.(*sync.Mutex).Unlock()
+
+	upload, err := GetResumableUpload(id, userID)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(upload).Error; err != nil {
+			return err
+		}
+		if upload.PublicID == nil {
+			return nil
+		}
+		// A request that was already used or has expired has nothing left to update
+		if err := syncTemporaryUpload(tx, upload.RequestID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return errS.Db(err)
+	}
+
+	if upload.PublicID != nil && objS != nil {
+		if err := objS.Delete(context.Background(), *upload.PublicID); err != nil {
+			log.Printf("Warning: Failed to delete staged file %s: %v", *upload.PublicID, err)
+		}
+	}
+	os.Remove(resumablePath(upload.ID))
+	resumableLocks.Delete(upload.ID)
+
+	return nil
+}
+
+// GetResumableRequest reports the user's files in a request in the same shape as /upload-images,
+// so the response can be passed to CreateQuestion as uploadResults once every file is finished
+func GetResumableRequest(requestID, userID string) (*models.UploadResponse, error) {
+	var uploads []models.ResumableUpload
+	if err := db.Where("request_id = ? AND uploader_id = ?", requestID, userID).
+		Order("position ASC").Find(&uploads).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+	if len(uploads) == 0 {
+		return nil, &models.BusinessError{Code: 404, Message: "Upload request not found"}
+	}
+
+	response := &models.UploadResponse{RequestID: requestID, Results: make([]models.UploadResult, len(uploads)), Success: true}
+	for i, upload := range uploads {
+		result := models.UploadResult{OriginalFilename: upload.Filename}
+		switch {
+		case upload.PublicID != nil:
+			result.PublicID = *upload.PublicID
+		case upload.Error != nil:
+			result.Error = *upload.Error
+		default:
+			result.Error = fmt.Sprintf("Upload incomplete: %d of %d bytes received", upload.Offset, upload.Length)
+		}
+		if result.Error != "" {
+			response.Success = false
+		}
+		response.Results[i] = result
+	}
+
+	return response, nil
+}
+
+// checkAppendOffset accepts a chunk only for an unfinished upload and only at the offset already received,
+// as tus requires, so a retried or out-of-order chunk can never overwrite or skip bytes
+func checkAppendOffset(upload *models.ResumableUpload, offset int64) error {
+	if upload.PublicID != nil || upload.Error != nil {
+		return &models.BusinessError{Code: 409, Message: "Upload is already finished"}
+	}
+	if offset != upload.Offset {
+		return &models.BusinessError{Code: 409, Message: fmt.Sprintf("Upload-Offset must be %d", upload.Offset)}
+	}
+	return nil
+}
+
+// appendChunk writes the body after the bytes already recorded, dropping any unrecorded tail a
+// previous interrupted write left behind, and never past the declared length
+func appendChunk(upload *models.ResumableUpload, body io.Reader) (int64, error) {
+	file, err := os.OpenFile(resumablePath(upload.ID), os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	if err := file.Truncate(upload.Offset); err != nil {
+		return 0, err
+	}
+	if _, err := file.Seek(upload.Offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	return io.Copy(file, io.LimitReader(body, upload.Length-upload.Offset))
+}
+
+// finishResumableUpload checks the assembled file is an image, stages it and adds it to its request
+func finishResumableUpload(upload *models.ResumableUpload) error {
+	if objS == nil {
+		return models.ErrInternal
+	}
+
+	file, err := os.Open(resumablePath(upload.ID))
+	if err != nil {
+		return fmt.Errorf("failed to open upload file: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("failed to read upload file: %w", err)
+	}
+	if contentType := http.DetectContentType(head[:n]); imageExtensions[contentType] == "" {
+		message := fmt.Sprintf("Unsupported file type: %s. Allowed types: JPEG, PNG, WebP", contentType)
+		upload.Error = &message
+		if err := db.Model(upload).Update("error", message).Error; err != nil {
+			return errS.Db(err)
+		}
+		os.Remove(resumablePath(upload.ID))
+		return errS.Invalid(message)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read upload file: %w", err)
+	}
+
+	publicID, err := objS.UploadTemp(context.Background(), file, upload.RequestID)
+	if err != nil {
+		return models.NewNetworkError(err.Error())
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(upload).Update("public_id", publicID).Error; err != nil {
+			return err
+		}
+		return syncTemporaryUpload(tx, upload.RequestID)
+	})
+	if err != nil {
+		if cleanupErr := objS.Delete(context.Background(), publicID); cleanupErr != nil {
+			log.Printf("Warning: Failed to delete staged file %s: %v", publicID, cleanupErr)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.BusinessError{Code: 410, Message: "Upload request expired or was already used"}
+		}
+		return errS.Db(err)
+	}
+	upload.PublicID = &publicID
+
+	os.Remove(resumablePath(upload.ID))
+	return nil
+}
+
+// syncTemporaryUpload rewrites a request's staged public IDs from its finished files, in page order.
+// The request row is locked first, so files finishing at the same time don't overwrite each other's IDs.
+func syncTemporaryUpload(tx *gorm.DB, requestID string) error {
+	var request models.TemporaryUpload
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("request_id = ? AND expires_at > ?", requestID, time.Now()).
+		First(&request).Error; err != nil {
+		return err
+	}
+
+	var publicIDs []string
+	if err := tx.Model(&models.ResumableUpload{}).Where("request_id = ? AND public_id IS NOT NULL", requestID).
+		Order("position ASC").Pluck("public_id", &publicIDs).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&request).Updates(map[string]interface{}{
+		"public_ids": strings.Join(publicIDs, ","),
+		"expires_at": time.Now().Add(tempUploadTTL),
+	}).Error
+}
+
+// resumablePath returns where the bytes of an upload are collected
+func resumablePath(id string) string {
+	return filepath.Join(resumableDir, id)
+}
+
+// startResumableCleanup periodically removes expired uploads and their partial files
+func startResumableCleanup() {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		cleanupResumableUploads()
+	}
+}
+
+// cleanupResumableUploads removes expired uploads and their partial files
+func cleanupResumableUploads() {
+	var expired []models.ResumableUpload
+	if err := db.Select("id").Where("expires_at < ?", time.Now()).Find(&expired).Error; err != nil {
+		log.Printf("Warning: Failed to list expired resumable uploads: %v", err)
+		return
+	}
+
+	for _, upload := range expired {
+		os.Remove(resumablePath(upload.ID))
+		resumableLocks.Delete(upload.ID)
+	}
+	if len(expired) > 0 {
+		db.Where("expires_at < ?", time.Now()).Delete(&models.ResumableUpload{})
+	}
+}
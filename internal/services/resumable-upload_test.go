@@ -0,0 +1,99 @@
+package services
+
+import (
+	"os"
+	"qb/pkg/models"
+	"strings"
+	"testing"
+)
+
+func TestCheckAppendOffset(t *testing.T) {
+	publicID := "qb_temp_uploads/a.png"
+	failure := "Uploaded file is not a supported image"
+
+	tests := []struct {
+		name     string
+		upload   models.ResumableUpload
+		offset   int64
+		wantCode int
+	}{
+		{"first chunk", models.ResumableUpload{Length: 100}, 0, 0},
+		{"next chunk", models.ResumableUpload{Length: 100, Offset: 40}, 40, 0},
+		{"empty chunk at the end retries staging", models.ResumableUpload{Length: 100, Offset: 100}, 100, 0},
+		{"chunk sent twice", models.ResumableUpload{Length: 100, Offset: 40}, 0, 409},
+		{"chunk skips ahead", models.ResumableUpload{Length: 100, Offset: 40}, 60, 409},
+		{"negative offset", models.ResumableUpload{Length: 100}, -1, 409},
+		{"already staged", models.ResumableUpload{Length: 100, Offset: 100, PublicID: &publicID}, 100, 409},
+		{"already failed", models.ResumableUpload{Length: 100, Offset: 100, Error: &failure}, 100, 409},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAppendOffset(&tt.upload, tt.offset)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("checkAppendOffset() = %v, want nil", err)
+				}
+				return
+			}
+			businessErr, ok := err.(*models.BusinessError)
+			if !ok || businessErr.Code != tt.wantCode {
+				t.Fatalf("checkAppendOffset() = %v, want code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAppendChunk(t *testing.T) {
+	resumableDir = t.TempDir()
+
+	tests := []struct {
+		name        string
+		onDisk      string // bytes already in the file, recorded or not
+		offset      int64  // bytes recorded as received
+		length      int64
+		chunk       string
+		wantWritten int64
+		wantFile    string
+	}{
+		{"first chunk", "", 0, 10, "hello", 5, "hello"},
+		{"next chunk", "hello", 5, 10, "world", 5, "helloworld"},
+		{"unrecorded tail of an interrupted write is dropped", "hellowo", 5, 10, "world", 5, "helloworld"},
+		{"bytes past the declared length are ignored", "hello", 5, 8, "world", 3, "hellowor"},
+		{"empty chunk", "hello", 5, 10, "", 0, "hello"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upload := &models.ResumableUpload{ID: string(rune('a' + i)), Offset: tt.offset, Length: tt.length}
+			if err := os.WriteFile(resumablePath(upload.ID), []byte(tt.onDisk), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			written, err := appendChunk(upload, strings.NewReader(tt.chunk))
+			if err != nil {
+				t.Fatalf("appendChunk: %v", err)
+			}
+			if written != tt.wantWritten {
+				t.Fatalf("appendChunk wrote %d bytes, want %d", written, tt.wantWritten)
+			}
+
+			got, err := os.ReadFile(resumablePath(upload.ID))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.wantFile {
+				t.Fatalf("file holds %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestAppendChunkMissingFile(t *testing.T) {
+	resumableDir = t.TempDir()
+
+	upload := &models.ResumableUpload{ID: "missing", Length: 10}
+	if _, err := appendChunk(upload, strings.NewReader("hello")); err == nil {
+		t.Fatal("appendChunk succeeded without the upload's file")
+	}
+}
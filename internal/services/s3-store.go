@@ -0,0 +1,313 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+// tempUploadRuleID names the lifecycle rule that expires abandoned temp uploads
+const tempUploadRuleID = "qb-expire-temp-uploads"
+
+// s3Store keeps pages in a private S3-compatible bucket (AWS S3, MinIO, R2, ...).
+// Stored URLs point at the server's FilesRoute, which redirects to a short-lived presigned GET,
+// so links saved in the database never expire. Temp uploads carry a temp_upload=true tag that a
+// bucket lifecycle rule expires after a day; promoting a page copies it without the tag.
+type s3Store struct {
+	client        *minio.Client
+	bucket        string
+	baseURL       string
+	presignExpiry time.Duration
+}
+
+// S3Config holds the connection settings of an S3-compatible store
+type S3Config struct {
+	Endpoint      string
+	AccessKey     string
+	SecretKey     string
+	Bucket        string
+	Region        string
+	UseSSL        bool
+	PresignExpiry time.Duration
+	BaseURL       string
+}
+
+// s3ConfigFromEnv reads the S3 settings. S3_ENDPOINT=localhost:9000 with S3_USE_SSL=false points
+// the store at a local MinIO.
+func s3ConfigFromEnv() S3Config {
+	useSSL, err := strconv.ParseBool(utils.GetEnv("S3_USE_SSL", "true"))
+	if err != nil {
+		log.Printf("Warning: Invalid S3_USE_SSL, falling back to true")
+		useSSL = true
+	}
+
+	expiry, err := time.ParseDuration(utils.GetEnv("S3_PRESIGN_EXPIRY", "15m"))
+	if err != nil || expiry <= 0 || expiry > 7*24*time.Hour {
+		log.Printf("Warning: Invalid S3_PRESIGN_EXPIRY, falling back to 15m")
+		expiry = 15 * time.Minute
+	}
+
+	return S3Config{
+		Endpoint:      utils.GetEnvFatal("S3_ENDPOINT"),
+		AccessKey:     utils.GetEnvFatal("S3_ACCESS_KEY"),
+		SecretKey:     utils.GetEnvFatal("S3_SECRET_KEY"),
+		Bucket:        utils.GetEnv("S3_BUCKET", "qb-uploads"),
+		Region:        utils.GetEnv("S3_REGION", ""),
+		UseSSL:        useSSL,
+		PresignExpiry: expiry,
+		BaseURL:       storagePublicURL(),
+	}
+}
+
+// NewS3Store connects to the bucket, creating it if needed, and installs the temp upload expiry rule
+func NewS3Store(config S3Config) (ObjectStore, error) {
+	client, err := minio.New(config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.AccessKey, config.SecretKey, ""),
+		Secure: config.UseSSL,
+		Region: config.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	exists, err := client.BucketExists(ctx, config.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach bucket %s: %w", config.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, config.Bucket, minio.MakeBucketOptions{Region: config.Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %s: %w", config.Bucket, err)
+		}
+	}
+
+	store := &s3Store{
+		client:        client,
+		bucket:        config.Bucket,
+		baseURL:       strings.TrimSuffix(config.BaseURL, "/"),
+		presignExpiry: config.PresignExpiry,
+	}
+
+	// Some providers or keys can't manage lifecycle rules; uploads still work, temp objects just linger
+	if err := store.ensureTempExpiry(ctx); err != nil {
+		log.Printf("Warning: Failed to set up expiry of temp uploads in bucket %s: %v", config.Bucket, err)
+	}
+
+	return store, nil
+}
+
+// UploadTemp puts an image under the temporary prefix, tagged for expiry and with its detected content type
+func (s *s3Store) UploadTemp(ctx context.Context, file io.Reader, requestID string) (string, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	contentType := http.DetectContentType(content)
+	extension := imageExtensions[contentType]
+	if extension == "" {
+		return "", fmt.Errorf("unsupported file type")
+	}
+
+	key := fmt.Sprintf("%s/%s.%s", tempFolder, uuid.New().String(), extension)
+	_, err = s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{
+		ContentType: contentType,
+		UserTags: map[string]string{
+			"temp_upload": "true",
+			"request":     requestID,
+			"expires":     strconv.FormatInt(time.Now().Add(tempUploadTTL).Unix(), 10),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload to S3: %w", err)
+	}
+
+	return key, nil
+}
+
+// Promote copies a temp object into the permanent folder without its expiry tag, then deletes the temp copy
+func (s *s3Store) Promote(ctx context.Context, tempKey, folder string) (*models.ImageAsset, error) {
+	if !strings.HasPrefix(tempKey, tempFolder+"/") {
+		return nil, fmt.Errorf("%s is not a temporary upload", tempKey)
+	}
+
+	key := folder + extractFilenameFromPublicID(tempKey)
+	if err := s.copy(ctx, tempKey, key); err != nil {
+		return nil, fmt.Errorf("failed to move file to permanent location: %w", err)
+	}
+
+	// Delete the temporary file
+	if err := s.Delete(ctx, tempKey); err != nil {
+		// Log error but don't fail the operation since the file was moved successfully
+		log.Printf("Warning: Failed to delete temp file %s: %v", tempKey, err)
+	}
+
+	return s.describe(ctx, key)
+}
+
+// Move copies an object to its new key and deletes the old one
+func (s *s3Store) Move(ctx context.Context, fromKey, toKey string) (string, error) {
+	if err := s.copy(ctx, fromKey, toKey); err != nil {
+		return "", err
+	}
+	if err := s.Delete(ctx, fromKey); err != nil {
+		// Both copies exist now; undo the copy so a retry starts from the same state
+		if undoErr := s.Delete(ctx, toKey); undoErr != nil {
+			log.Printf("Warning: Failed to remove copy %s after aborted move: %v", toKey, undoErr)
+		}
+		return "", err
+	}
+	return s.URL(toKey)
+}
+
+// Delete removes a single object; S3 treats a missing object as already deleted
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object under the prefix in batches
+func (s *s3Store) DeletePrefix(ctx context.Context, prefix string) error {
+	// An empty ID would turn qb_questions/<id>/ into a prefix that never matches, but never risk the whole root
+	if prefix == "" || strings.Contains(prefix, "//") {
+		return fmt.Errorf("invalid storage prefix %q", prefix)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var listErr error
+	objects := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(objects)
+		for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+			if object.Err != nil {
+				listErr = object.Err
+				return
+			}
+			select {
+			case objects <- object:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for result := range s.client.RemoveObjects(ctx, s.bucket, objects, minio.RemoveObjectsOptions{}) {
+		if result.Err != nil {
+			return fmt.Errorf("failed to delete %s: %w", result.ObjectName, result.Err)
+		}
+	}
+	if listErr != nil {
+		return fmt.Errorf("failed to list objects under %s: %w", prefix, listErr)
+	}
+
+	return nil
+}
+
+// URL builds the stable link of an object, served through a redirect to a presigned GET
+func (s *s3Store) URL(key string) (string, error) {
+	return s.baseURL + "/" + key, nil
+}
+
+// JPEGURL returns a presigned GET directly; objects are stored as uploaded, so PDFs convert WebP pages themselves
+func (s *s3Store) JPEGURL(key string) (string, error) {
+	return s.PresignGet(context.Background(), key)
+}
+
+// PresignGet returns a short-lived link that reads the object straight from the bucket
+func (s *s3Store) PresignGet(ctx context.Context, key string) (string, error) {
+	presigned, err := s.client.PresignedGetObject(ctx, s.bucket, key, s.presignExpiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return presigned.String(), nil
+}
+
+// copy duplicates an object server-side, keeping its content type and dropping its tags
+func (s *s3Store) copy(ctx context.Context, fromKey, toKey string) error {
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: s.bucket, Object: toKey, ReplaceTags: true, UserTags: map[string]string{}},
+		minio.CopySrcOptions{Bucket: s.bucket, Object: fromKey},
+	)
+	return err
+}
+
+// describe reads an object's size and, for JPEG and PNG, its dimensions
+func (s *s3Store) describe(ctx context.Context, key string) (*models.ImageAsset, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	info, err := object.Stat()
+	if err != nil {
+		return nil, err
+	}
+	url, err := s.URL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &models.ImageAsset{
+		PublicID: key,
+		URL:      url,
+		Bytes:    int(info.Size),
+		Format:   utils.FormatFromURL(key),
+	}
+	if config, _, err := image.DecodeConfig(object); err == nil {
+		asset.Width = config.Width
+		asset.Height = config.Height
+	}
+
+	return asset, nil
+}
+
+// ensureTempExpiry adds or refreshes the lifecycle rule expiring temp uploads, keeping any other rules
+func (s *s3Store) ensureTempExpiry(ctx context.Context) error {
+	config, err := s.client.GetBucketLifecycle(ctx, s.bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration" {
+			return err
+		}
+		config = lifecycle.NewConfiguration()
+	}
+
+	rules := make([]lifecycle.Rule, 0, len(config.Rules)+1)
+	for _, rule := range config.Rules {
+		if rule.ID != tempUploadRuleID {
+			rules = append(rules, rule)
+		}
+	}
+	config.Rules = append(rules, lifecycle.Rule{
+		ID:     tempUploadRuleID,
+		Status: "Enabled",
+		RuleFilter: lifecycle.Filter{
+			Tag: lifecycle.Tag{Key: "temp_upload", Value: "true"},
+		},
+		Expiration: lifecycle.Expiration{
+			Days: lifecycle.ExpirationDays(tempUploadTTL / (24 * time.Hour)),
+		},
+	})
+
+	return s.client.SetBucketLifecycle(ctx, s.bucket, config)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"qb/pkg/models"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Relevance weights: a course title/code match says more about a paper than a word in its tips
+const (
+	courseMatchWeight   = 2.0
+	questionMatchWeight = 1.0
+	sessionYearBoost    = 1.0
+	maxCourseHits       = 10
+)
+
+const (
+	courseMatch   = "MATCH(courses.id, courses.title, courses.description) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	questionMatch = "MATCH(questions.lecturer, questions.tips) AGAINST (? IN NATURAL LANGUAGE MODE)"
+)
+
+// searchHit is a matched row ID with its relevance score
+type searchHit struct {
+	ID    string
+	Score float64
+}
+
+// Search ranks courses and the questions the user can see against a free-text query using MySQL FULLTEXT indexes.
+// Four-digit numbers in the query (e.g. 2023) also boost questions from sessions spanning that year.
+func Search(q, userID, role string, page, limit int) (*models.SearchResponse, error) {
+	q = strings.TrimSpace(q)
+	if len(q) < 2 {
+		return nil, errS.Invalid("q must be at least 2 characters")
+	}
+
+	courses, err := searchCourses(q)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := searchQuestions(q, userID, role, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SearchResponse{Query: q, Courses: courses, Questions: questions}, nil
+}
+
+// searchCourses returns the best course matches, highest score first
+func searchCourses(q string) ([]models.CourseSearchHit, error) {
+	var hits []searchHit
+	if err := db.Model(&models.Course{}).
+		Select("courses.id, "+courseMatch+" AS score", q).
+		Where(courseMatch, q).
+		Order("score DESC").Limit(maxCourseHits).
+		Scan(&hits).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	var courses []models.Course
+	if err := db.Where("id IN ?", hitIDs(hits)).Find(&courses).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	byID := make(map[string]models.Course, len(courses))
+	for _, course := range courses {
+		byID[course.ID] = course
+	}
+
+	results := make([]models.CourseSearchHit, 0, len(hits))
+	for _, hit := range hits {
+		if course, ok := byID[hit.ID]; ok {
+			results = append(results, models.CourseSearchHit{Course: course, Score: hit.Score})
+		}
+	}
+
+	return results, nil
+}
+
+// searchQuestions returns one page of visible questions ranked by course, question and session-year relevance
+func searchQuestions(q, userID, role string, page, limit int) ([]models.QuestionSearchHit, error) {
+	score := gorm.Expr("? * "+courseMatch+" + ? * "+questionMatch,
+		courseMatchWeight, q, questionMatchWeight, q)
+	if years := extractYears(q); len(years) > 0 {
+		score = gorm.Expr("? + CASE WHEN sessions.start_date IN ? OR sessions.end_date IN ? THEN ? ELSE 0 END",
+			score, years, years, sessionYearBoost)
+	}
+
+	offset := (page - 1) * limit
+
+	var hits []searchHit
+	if err := db.Table("questions").
+		Select("questions.id, ? AS score", score).
+		Joins("JOIN courses ON courses.id = questions.course_id").
+		Joins("JOIN sessions ON sessions.id = questions.session_id").
+		Scopes(visibleTo(userID, role)).
+		// Filtering on the matches lets the FULLTEXT indexes narrow the rows before they are scored
+		Where("("+courseMatch+") OR ("+questionMatch+")", q, q).
+		Order("score DESC, questions.created_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&hits).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	var questions []models.Question
+	if err := db.Preload("Course").Preload("Session").Preload("Images", orderedImages).
+		Where("id IN ?", hitIDs(hits)).Find(&questions).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	byID := make(map[string]models.Question, len(questions))
+	for _, question := range questions {
+		byID[question.ID] = question
+	}
+
+	results := make([]models.QuestionSearchHit, 0, len(hits))
+	for _, hit := range hits {
+		if question, ok := byID[hit.ID]; ok {
+			results = append(results, models.QuestionSearchHit{Question: question, Score: hit.Score})
+		}
+	}
+
+	return results, nil
+}
+
+// extractYears collects plausible calendar years from the query terms
+func extractYears(q string) []int {
+	var years []int
+	for _, term := range strings.Fields(q) {
+		if len(term) != 4 {
+			continue
+		}
+		if year, err := strconv.Atoi(term); err == nil && year >= 1000 && year <= 9999 {
+			years = append(years, year)
+		}
+	}
+	return years
+}
+
+// hitIDs returns the IDs of search hits, never empty so IN clauses stay valid
+func hitIDs(hits []searchHit) []string {
+	ids := make([]string, 0, len(hits)+1)
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	if len(ids) == 0 {
+		ids = append(ids, "")
+	}
+	return ids
+}
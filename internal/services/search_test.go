@@ -0,0 +1,52 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractYears(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want []int
+	}{
+		{"no numbers", "data structures", nil},
+		{"single year", "csc201 2023", []int{2023}},
+		{"several years in order", "2021 exam 2022", []int{2021, 2022}},
+		{"extra whitespace", "  2019\tpast\nquestions ", []int{2019}},
+		{"course codes are not years", "csc201 mth1010", nil},
+		{"too short", "exam 202", nil},
+		{"too long", "exam 20231", nil},
+		{"leading zero is below the range", "0999 paper", nil},
+		{"lowest accepted year", "1000", []int{1000}},
+		{"signed numbers are not years", "+202 -2023", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractYears(tt.q); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("extractYears(%q) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHitIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		hits []searchHit
+		want []string
+	}{
+		{"no hits keeps the IN clause valid", nil, []string{""}},
+		{"keeps rank order", []searchHit{{ID: "b", Score: 2}, {ID: "a", Score: 1}}, []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hitIDs(tt.hits); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("hitIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"qb/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+// DeleteSession deletes a session by its ID along with its questions and their images
+func DeleteSession(id string) error {
+	var questionIDs []string
+	if err := db.Model(&models.Question{}).Where("session_id = ?", id).Pluck("id", &questionIDs).Error; err != nil {
+		return errS.Db(err)
+	}
+
+	return deleteQuestionsWithAssets(questionIDs, func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Session{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrNotFound
+		}
+		return nil
+	})
+}
@@ -3,10 +3,8 @@ package services
 import (
 	"log"
 	"qb/pkg/database"
-	"qb/pkg/utils"
 	"time"
 
-	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
 )
@@ -14,12 +12,13 @@ import (
 var (
 	db   *gorm.DB
 	valS *validator.Validate
-	cldS *cloudinary.Cloudinary
+	objS ObjectStore
 	errS *ErrorService
 	
 	// Rate limiter instances - these need to be per-handler since they have different configs
 	uploadRateLimiter   *RateLimitService
 	generalRateLimiter  *RateLimitService
+	reportRateLimiter   *RateLimitService
 )
 
 // InitServices initializes all shared service dependencies once
@@ -33,13 +32,8 @@ func InitServices() {
 	// Initialize validator
 	valS = validator.New()
 
-	// Initialize cloudinary
-	cloudinaryURL := utils.GetEnvFatal("CLOUDINARY_URL")
-	cld, err := cloudinary.NewFromURL(cloudinaryURL)
-	if err != nil {
-		log.Fatalf("Failed to initialize Cloudinary: %v", err)
-	}
-	cldS = cld
+	// Initialize image storage
+	InitObjectStore()
 
 	// Initialize database (use existing connection)
 	database.ConnectDB()
@@ -54,6 +48,24 @@ func InitServices() {
 	// Initialize rate limiters
 	InitRateLimiters()
 
+	// Prepare the rendered PDF cache
+	InitPrintCache()
+
+	// Load the pagination cursor signing key
+	InitCursorSigner()
+
+	// Start the deduplicating view counter
+	InitViewCounter()
+
+	// Load how many reports hide a question
+	InitReportThreshold()
+
+	// Prepare the resumable upload directory and its cleanup
+	InitResumableUploads()
+
+	// Bring question IDs in line with the current scheme before any request can create or edit questions
+	MigrateQuestionIDs()
+
 	log.Println("All services initialized successfully")
 }
 
@@ -61,6 +73,7 @@ func InitServices() {
 func InitRateLimiters() {
 	uploadRateLimiter = NewRateLimitService(50, time.Hour)   // 50 uploads per hour
 	generalRateLimiter = NewRateLimitService(200, time.Hour) // 200 requests per hour
+	reportRateLimiter = NewRateLimitService(10, time.Hour)   // 10 reports per user per hour
 }
 
 // GetUploadRateLimiter returns the upload rate limiter
@@ -73,6 +86,11 @@ func GetGeneralRateLimiter() *RateLimitService {
 	return generalRateLimiter
 }
 
+// GetReportRateLimiter returns the per-user report rate limiter
+func GetReportRateLimiter() *RateLimitService {
+	return reportRateLimiter
+}
+
 // GetErrorService returns the shared error service
 func GetErrorService() *ErrorService {
 	return errS
@@ -87,6 +105,6 @@ func GetValidator() *validator.Validate {
 	return valS
 }
 
-func GetCloudinary() *cloudinary.Cloudinary {
-	return cldS
+func GetObjectStore() ObjectStore {
+	return objS
 }
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"log"
+	"qb/pkg/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateSolution attaches a worked solution to a question the user can see.
+// Pages come from a staged /upload-images request, just like question pages; the solution starts pending.
+func CreateSolution(questionID, userID, role string, input models.CreateSolutionDTO) (*models.Solution, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	var body *string
+	if input.Body != nil {
+		if trimmed := strings.TrimSpace(*input.Body); trimmed != "" {
+			body = &trimmed
+		}
+	}
+
+	visibleID, err := getVisibleQuestionID(questionID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	tempPublicIDs, err := processUploadResults(input.UploadResults)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil && len(tempPublicIDs) == 0 {
+		return nil, errS.Invalid("A solution needs a body, uploaded pages or both")
+	}
+
+	// The ID is chosen up front so pages can go straight into the solution's folder
+	solutionID := uuid.New().String()
+	assets, processingStatus := promoteStagedImages(tempPublicIDs, solutionFolder(solutionID))
+	if body == nil && len(assets) == 0 {
+		return nil, models.NewUploadError("Failed to move the solution pages to permanent storage")
+	}
+
+	images := make([]models.SolutionImage, len(assets))
+	for i, asset := range assets {
+		images[i] = models.SolutionImage{SolutionID: solutionID, Page: i + 1, ImageAsset: asset}
+	}
+
+	solution := models.Solution{
+		ID:               solutionID,
+		QuestionID:       visibleID,
+		AuthorID:         &userID,
+		Body:             body,
+		Images:           images,
+		Approved:         false,
+		ModerationStatus: models.ModerationStatusPending,
+		ProcessingStatus: &processingStatus,
+	}
+
+	if err := db.Create(&solution).Error; err != nil {
+		if cleanupErr := DeleteSolutionAssets(solutionID); cleanupErr != nil {
+			log.Printf("Warning: Failed to clean up pages of unsaved solution %s: %v", solutionID, cleanupErr)
+		}
+		return nil, errS.Db(err)
+	}
+
+	return &solution, nil
+}
+
+// GetSolutions lists the solutions of a question the user can see, oldest first.
+// Everyone sees approved solutions, authors also see their own pending or rejected ones, and admins see all.
+func GetSolutions(questionID, userID, role string) ([]models.Solution, error) {
+	visibleID, err := getVisibleQuestionID(questionID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.Preload("Author", publicAuthor).Preload("Images", orderedImages).
+		Where("question_id = ?", visibleID)
+	switch {
+	case role == string(models.RoleAdmin):
+	case userID == "":
+		query = query.Where("approved = ?", true)
+	default:
+		query = query.Where("(approved = ? OR author_id = ?)", true, userID)
+	}
+
+	var solutions []models.Solution
+	if err := query.Order("created_at ASC, id ASC").Find(&solutions).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return solutions, nil
+}
+
+// DeleteSolution removes a solution and its pages.
+// Authors may delete their own solutions until they are approved; admins may delete any solution.
+func DeleteSolution(questionID, solutionID, userID, role string) error {
+	var solution models.Solution
+	if err := db.Where("id = ? AND question_id = ?", solutionID, questionID).First(&solution).Error; err != nil {
+		return errS.Db(err, "Solution")
+	}
+
+	if role != string(models.RoleAdmin) {
+		if solution.AuthorID == nil || *solution.AuthorID != userID {
+			return models.ErrForbidden
+		}
+		if solution.Approved {
+			return &models.BusinessError{Code: 403, Message: "Approved solutions can only be deleted by an admin"}
+		}
+	}
+
+	// Pages go first so a storage failure leaves the row in place and the delete can be retried
+	if err := DeleteSolutionAssets(solution.ID); err != nil {
+		return models.NewNetworkError(err.Error())
+	}
+
+	if err := db.Delete(&solution).Error; err != nil {
+		return errS.Db(err)
+	}
+
+	return nil
+}
+
+// GetPendingSolutions lists solutions awaiting moderation, oldest first
+func GetPendingSolutions(page, limit int) ([]models.Solution, error) {
+	var solutions []models.Solution
+
+	offset := (page - 1) * limit
+
+	if err := db.Preload("Author", publicAuthor).Preload("Images", orderedImages).
+		Where("moderation_status = ?", models.ModerationStatusPending).
+		Order("created_at ASC").Offset(offset).Limit(limit).Find(&solutions).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return solutions, nil
+}
+
+// ApproveSolution publishes a solution; the reason is optional
+func ApproveSolution(id string, input models.ModerateQuestionDTO) (*models.Solution, error) {
+	return moderateSolution(id, models.ModerationStatusApproved, input)
+}
+
+// RejectSolution hides a solution and records why, so the author can see it
+func RejectSolution(id string, input models.ModerateQuestionDTO) (*models.Solution, error) {
+	if strings.TrimSpace(input.Reason) == "" {
+		return nil, errS.Invalid("A reason is required when rejecting a solution")
+	}
+	return moderateSolution(id, models.ModerationStatusRejected, input)
+}
+
+// moderateSolution moves a solution to the target moderation status
+func moderateSolution(id string, status models.ModerationStatus, input models.ModerateQuestionDTO) (*models.Solution, error) {
+	if err := valS.Struct(input); err != nil {
+		return nil, errS.Invalid(err)
+	}
+
+	var solution models.Solution
+	if err := db.Where("id = ?", id).First(&solution).Error; err != nil {
+		return nil, errS.Db(err, "Solution")
+	}
+
+	if solution.ModerationStatus == status {
+		return nil, errS.Invalid("Solution is already " + strings.ToLower(string(status)))
+	}
+
+	var reason *string
+	if trimmed := strings.TrimSpace(input.Reason); trimmed != "" {
+		reason = &trimmed
+	}
+	now := time.Now()
+
+	solution.Approved = status == models.ModerationStatusApproved
+	solution.ModerationStatus = status
+	solution.ModerationReason = reason
+	solution.ModeratedAt = &now
+
+	updates := map[string]interface{}{
+		"approved":          solution.Approved,
+		"moderation_status": status,
+		"moderation_reason": reason,
+		"moderated_at":      now,
+	}
+	if err := db.Model(&solution).Updates(updates).Error; err != nil {
+		return nil, errS.Db(err)
+	}
+
+	return &solution, nil
+}
+
+// approvedSolutions preloads a question's published solutions, oldest first
+func approvedSolutions(tx *gorm.DB) *gorm.DB {
+	return tx.Where("approved = ?", true).Order("created_at ASC, id ASC")
+}
+
+// publicAuthor preloads only the user fields that are safe to show next to their contributions
+func publicAuthor(tx *gorm.DB) *gorm.DB {
+	return tx.Select("id", "first_name", "last_name", "username", "role")
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"log"
 	"mime/multipart"
 	"qb/pkg/models"
 	"qb/pkg/utils"
@@ -26,7 +27,7 @@ func ProcessImageUploads(files []*multipart.FileHeader, requestID string) (model
 		}
 	}
 
-	// Use bounded concurrency to prevent overwhelming Cloudinary
+	// Use bounded concurrency to prevent overwhelming the storage backend
 	const maxConcurrentUploads = 10
 	semaphore := make(chan struct{}, maxConcurrentUploads)
 	
@@ -48,8 +49,8 @@ func ProcessImageUploads(files []*multipart.FileHeader, requestID string) (model
 				OriginalFilename: file.Filename,
 			}
 
-			// Upload file to Cloudinary
-			_, publicID, err := UploadFileToTemp(file, requestID)
+			// Upload file to storage
+			publicID, err := UploadFileToTemp(file, requestID)
 			if err != nil {
 				result.Error = err.Error()
 			} else {
@@ -78,7 +79,7 @@ func ProcessImageUploads(files []*multipart.FileHeader, requestID string) (model
 	if len(successfulPublicIDs) > 0 {
 		if err := StoreTemporaryUpload(requestID, successfulPublicIDs); err != nil {
 			// Log the error but don't fail the upload response since files were uploaded successfully
-			fmt.Printf("Warning: Failed to store request tracking: %v\n", err)
+			log.Printf("Warning: Failed to store request tracking: %v", err)
 		}
 	}
 
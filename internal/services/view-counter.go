@@ -0,0 +1,135 @@
+package services
+
+import (
+	"log"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// viewFlushInterval is how often buffered views are written to the database
+const viewFlushInterval = 10 * time.Second
+
+// ViewCounter deduplicates question views per viewer and writes them in batches off the request path
+type ViewCounter struct {
+	seen    map[string]time.Time
+	pending []models.EngagementEvent
+	mutex   sync.Mutex
+	window  time.Duration
+}
+
+var viewCounter *ViewCounter
+
+// InitViewCounter creates the shared view counter using VIEW_DEDUP_WINDOW (e.g. "30m")
+func InitViewCounter() {
+	window, err := time.ParseDuration(utils.GetEnv("VIEW_DEDUP_WINDOW", "30m"))
+	if err != nil || window <= 0 {
+		log.Printf("Warning: Invalid VIEW_DEDUP_WINDOW, falling back to 30m")
+		window = 30 * time.Minute
+	}
+	viewCounter = NewViewCounter(window)
+}
+
+// NewViewCounter creates a view counter that counts each viewer once per window
+func NewViewCounter(window time.Duration) *ViewCounter {
+	counter := &ViewCounter{
+		seen:   make(map[string]time.Time),
+		window: window,
+	}
+
+	// Start flush and cleanup goroutine
+	go counter.startFlushRoutine()
+
+	return counter
+}
+
+// RecordView buffers a view of the question unless the viewer already viewed it within the window.
+// The viewer is the user ID when signed in, otherwise the client IP.
+func (s *ViewCounter) RecordView(questionID, userID, ip string) bool {
+	viewer := "ip:" + ip
+	if userID != "" {
+		viewer = "user:" + userID
+	}
+	key := viewer + "|" + questionID
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	if last, exists := s.seen[key]; exists && now.Sub(last) < s.window {
+		return false
+	}
+
+	s.seen[key] = now
+	s.pending = append(s.pending, newEngagementEvent(questionID, userID, models.EngagementTypeView))
+	return true
+}
+
+// startFlushRoutine periodically writes buffered views and forgets viewers outside the window
+func (s *ViewCounter) startFlushRoutine() {
+	flush := time.NewTicker(viewFlushInterval)
+	defer flush.Stop()
+	cleanup := time.NewTicker(10 * time.Minute) // Clean up every 10 minutes
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-flush.C:
+			s.flush()
+		case <-cleanup.C:
+			s.cleanupOldViews()
+		}
+	}
+}
+
+// flush writes the buffered views as engagement events plus one atomic counter increment per question.
+// Each question is committed on its own, so a question deleted or renamed while its views were
+// buffered only drops its own views.
+func (s *ViewCounter) flush() {
+	s.mutex.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	byQuestion := make(map[string][]models.EngagementEvent)
+	for _, event := range pending {
+		byQuestion[event.QuestionID] = append(byQuestion[event.QuestionID], event)
+	}
+
+	for questionID, events := range byQuestion {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.Question{}).Where("id = ?", questionID).
+				Update("views", gorm.Expr("views + ?", len(events)))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				// The question is gone, so there is nothing left to count the views against
+				return nil
+			}
+			return recordEngagement(tx, events)
+		}); err != nil {
+			log.Printf("Warning: Failed to record %d views of question %s: %v", len(events), questionID, err)
+		}
+	}
+}
+
+// cleanupOldViews removes viewers whose last view is outside the window
+func (s *ViewCounter) cleanupOldViews() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cutoff := time.Now().Add(-s.window)
+	for key, last := range s.seen {
+		if last.Before(cutoff) {
+			delete(s.seen, key)
+		}
+	}
+}
@@ -30,6 +30,11 @@ func main() {
 	
 	r.RedirectTrailingSlash = false
 
+	// Client IPs key rate limits and view counts, so forwarded headers are only believed from known proxies
+	if err := r.SetTrustedProxies(utils.GetEnvList("TRUSTED_PROXIES")); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	routes.SetupRoutes(r)
 
 	port := utils.GetEnvFatal("PORT")
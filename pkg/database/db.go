@@ -34,4 +34,9 @@ func ConnectDB() {
 	if err != nil {
 		log.Fatalf("Failed to auto-migrate database: %v", err)
 	}
+
+	// Backfill data for columns added after rows already existed
+	if err := RunDataMigrations(DB); err != nil {
+		log.Fatalf("Failed to run data migrations: %v", err)
+	}
 }
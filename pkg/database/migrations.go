@@ -0,0 +1,113 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"qb/pkg/models"
+	"qb/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// dataMigration is an idempotent backfill that runs after AutoMigrate on every start
+type dataMigration struct {
+	name string
+	run  func(tx *gorm.DB) error
+}
+
+var dataMigrations = []dataMigration{
+	{name: "backfill question moderation status", run: backfillModerationStatus},
+	{name: "move question image links into question_images", run: migrateImageLinks},
+	{name: "keep moderation events of deleted questions", run: keepModerationEvents},
+}
+
+// RunDataMigrations applies every data migration in order
+func RunDataMigrations(db *gorm.DB) error {
+	for _, m := range dataMigrations {
+		if err := db.Transaction(m.run); err != nil {
+			return fmt.Errorf("%s: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// backfillModerationStatus marks questions approved before moderation existed as APPROVED
+func backfillModerationStatus(tx *gorm.DB) error {
+	return tx.Model(&models.Question{}).
+		Where("approved = ? AND moderation_status = ?", true, models.ModerationStatusPending).
+		Update("moderation_status", models.ModerationStatusApproved).Error
+}
+
+// migrateImageLinks copies the legacy questions.image_links JSON array into question_images rows,
+// then drops the column so it cannot drift from the new table
+func migrateImageLinks(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&models.Question{}, "image_links") {
+		return nil
+	}
+
+	var rows []struct {
+		ID         string
+		ImageLinks *string
+	}
+	if err := tx.Table("questions").Select("id", "image_links").Where("image_links IS NOT NULL").Find(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var links []string
+		if err := json.Unmarshal([]byte(*row.ImageLinks), &links); err != nil {
+			return fmt.Errorf("question %s: %w", row.ID, err)
+		}
+		if len(links) == 0 {
+			continue
+		}
+
+		var existing int64
+		if err := tx.Model(&models.QuestionImage{}).Where("question_id = ?", row.ID).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			continue
+		}
+
+		images := make([]models.QuestionImage, len(links))
+		for i, link := range links {
+			images[i] = models.QuestionImage{
+				QuestionID: row.ID,
+				Page:       i + 1,
+				ImageAsset: models.ImageAsset{
+					PublicID: utils.PublicIDFromURL(link),
+					URL:      link,
+					Format:   utils.FormatFromURL(link),
+				},
+			}
+		}
+		if err := tx.Create(&images).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Migrator().DropColumn(&models.Question{}, "image_links")
+}
+
+// keepModerationEvents replaces the moderation_events foreign key that deleted a question's history
+// along with it. AutoMigrate only creates missing constraints, so the old one has to be swapped here.
+func keepModerationEvents(tx *gorm.DB) error {
+	const constraint = "fk_moderation_events_question"
+
+	var deleteRule string
+	if err := tx.Raw(
+		"SELECT delete_rule FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS WHERE constraint_schema = ? AND table_name = ? AND constraint_name = ?",
+		tx.Migrator().CurrentDatabase(), "moderation_events", constraint,
+	).Scan(&deleteRule).Error; err != nil {
+		return err
+	}
+	if deleteRule != "CASCADE" {
+		return nil
+	}
+
+	if err := tx.Migrator().DropConstraint(&models.ModerationEvent{}, "Question"); err != nil {
+		return err
+	}
+	return tx.Migrator().CreateConstraint(&models.ModerationEvent{}, "Question")
+}
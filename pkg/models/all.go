@@ -7,6 +7,19 @@ var AllModels = []any{
 	&Level{},
 	&Course{},
 	&Question{},
+	&QuestionImage{},
 	&Session{},
 	&TemporaryUpload{},
+	&ResumableUpload{},
+	&ModerationEvent{},
+	&EngagementEvent{},
+	&QuestionDailyStat{},
+	&QuestionRating{},
+	&Report{},
+	&Solution{},
+	&SolutionImage{},
+	&Comment{},
+	&Bookmark{},
+	&Collection{},
+	&CollectionItem{},
 }
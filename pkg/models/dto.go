@@ -89,3 +89,101 @@ type CourseFilterDTO struct {
 	Level    int    `uri:"level" validate:"required,oneof=100 200 300 400 500"`
 	Semester int    `uri:"semester" validate:"required,oneof=1 2"`
 }
+
+// ModerateQuestionDTO is the input for approving or rejecting a question
+type ModerateQuestionDTO struct {
+	Reason string `json:"reason" validate:"max=1000"`
+}
+
+// CreateCommentDTO is the input for starting a thread or replying to one
+type CreateCommentDTO struct {
+	Body     string `json:"body" binding:"required" validate:"required,max=5000"`
+	ParentID *uint  `json:"parentId,omitempty"`
+}
+
+// UpdateCommentDTO is the input for editing a comment
+type UpdateCommentDTO struct {
+	Body string `json:"body" binding:"required" validate:"required,max=5000"`
+}
+
+// CreateCollectionDTO is the input for creating a collection
+type CreateCollectionDTO struct {
+	Name        string  `json:"name" binding:"required" validate:"required,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Shared      bool    `json:"shared"`
+}
+
+// UpdateCollectionDTO is a partial update of a collection; nil fields are left unchanged
+type UpdateCollectionDTO struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Shared      *bool   `json:"shared,omitempty"`
+}
+
+// AddCollectionItemDTO adds a question to the end of a collection
+type AddCollectionItemDTO struct {
+	QuestionID string `json:"questionId" binding:"required" validate:"required"`
+}
+
+// ReorderCollectionDTO lists every question of a collection in its new order
+type ReorderCollectionDTO struct {
+	QuestionIDs []string `json:"questionIds" binding:"required" validate:"required,min=1"`
+}
+
+// RateQuestionDTO sets the user's rating of a question; nil fields keep their previous value
+type RateQuestionDTO struct {
+	Difficulty *int  `json:"difficulty,omitempty" validate:"omitempty,min=1,max=5"`
+	Readable   *bool `json:"readable,omitempty"`
+	Complete   *bool `json:"complete,omitempty"`
+}
+
+// RatingResponse is the user's rating together with the question's updated aggregates
+type RatingResponse struct {
+	Rating  *QuestionRating `json:"rating,omitempty"`
+	Summary RatingSummary   `json:"summary"`
+}
+
+// CreateReportDTO is the input for reporting a question
+type CreateReportDTO struct {
+	Reason  ReportReason `json:"reason" binding:"required" validate:"required,oneof=WRONG_COURSE WRONG_SESSION UNREADABLE INCOMPLETE INAPPROPRIATE OTHER"`
+	Details *string      `json:"details,omitempty" validate:"omitempty,max=2000"`
+}
+
+// ReviewReportDTO is the input for resolving or dismissing a report; the note is optional
+type ReviewReportDTO struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+// SearchResponse holds ranked course and question matches for a search query
+type SearchResponse struct {
+	Query     string              `json:"query"`
+	Courses   []CourseSearchHit   `json:"courses"`
+	Questions []QuestionSearchHit `json:"questions"`
+}
+
+// CourseSearchHit is a course with its relevance score
+type CourseSearchHit struct {
+	Course
+	Score float64 `json:"score"`
+}
+
+// QuestionSearchHit is a question with its relevance score
+type QuestionSearchHit struct {
+	Question
+	Score float64 `json:"score"`
+}
+
+// TrendingFilterDTO holds the query parameters accepted by the trending listing
+type TrendingFilterDTO struct {
+	DepartmentID string `form:"departmentId" validate:"omitempty,max=3"`
+	Level        int    `form:"level" validate:"omitempty,oneof=100 200 300 400 500"`
+	Days         int    `form:"days" validate:"omitempty,min=1,max=90"`
+	Page         int    `form:"page"`
+	Limit        int    `form:"limit"`
+}
+
+// TrendingQuestion is a question with its time-decayed engagement score
+type TrendingQuestion struct {
+	Question
+	Score float64 `json:"score"`
+}
@@ -5,5 +5,16 @@ type APIResponse struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
 	Error   interface{} `json:"error,omitempty"`
+}
+
+// PaginationMeta describes one page of a listing.
+// Total and Page are left out of cursor-paged responses, which skip counting.
+type PaginationMeta struct {
+	Total      *int64 `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	HasNext    bool   `json:"hasNext"`
+	NextCursor string `json:"nextCursor,omitempty"`
 } 
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Role represents the Role enum in Prisma.
@@ -16,8 +18,63 @@ const (
 type QuestionType string
 
 const (
-	QuestionTypeTest QuestionType = "TEST"
-	QuestionTypeExam QuestionType = "EXAM"
+	QuestionTypeTest       QuestionType = "TEST"
+	QuestionTypeExam       QuestionType = "EXAM"
+	QuestionTypeResit      QuestionType = "RESIT"
+	QuestionTypeQuiz       QuestionType = "QUIZ"
+	QuestionTypeAssignment QuestionType = "ASSIGNMENT"
+	QuestionTypePractical  QuestionType = "PRACTICAL"
+	QuestionTypeMockExam   QuestionType = "MOCK_EXAM"
+)
+
+// ModerationStatus represents the review state of an uploaded question.
+type ModerationStatus string
+
+const (
+	ModerationStatusPending  ModerationStatus = "PENDING"
+	ModerationStatusApproved ModerationStatus = "APPROVED"
+	ModerationStatusRejected ModerationStatus = "REJECTED"
+)
+
+// ModerationAction describes what caused a question's moderation state to change.
+type ModerationAction string
+
+const (
+	ModerationActionSubmit   ModerationAction = "SUBMIT"
+	ModerationActionResubmit ModerationAction = "RESUBMIT"
+	ModerationActionApprove  ModerationAction = "APPROVE"
+	ModerationActionReject   ModerationAction = "REJECT"
+	ModerationActionHide     ModerationAction = "HIDE"
+)
+
+// ReportReason is the category a user picks when reporting a question.
+type ReportReason string
+
+const (
+	ReportReasonWrongCourse   ReportReason = "WRONG_COURSE"
+	ReportReasonWrongSession  ReportReason = "WRONG_SESSION"
+	ReportReasonUnreadable    ReportReason = "UNREADABLE"
+	ReportReasonIncomplete    ReportReason = "INCOMPLETE"
+	ReportReasonInappropriate ReportReason = "INAPPROPRIATE"
+	ReportReasonOther         ReportReason = "OTHER"
+)
+
+// ReportStatus represents where a report is in the admin queue.
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "OPEN"
+	ReportStatusResolved  ReportStatus = "RESOLVED"
+	ReportStatusDismissed ReportStatus = "DISMISSED"
+)
+
+// EngagementType describes how a user engaged with a question.
+type EngagementType string
+
+const (
+	EngagementTypeView     EngagementType = "VIEW"
+	EngagementTypeDownload EngagementType = "DOWNLOAD"
+	EngagementTypeBookmark EngagementType = "BOOKMARK"
 )
 
 // CourseStatus represents the CourseStatus enum in Prisma.
@@ -123,11 +180,17 @@ type Session struct {
 // - ID: Translated from String @id @map("_id").
 // - CourseID: Foreign key to Course (6-character course code)
 // - SessionID/UploaderID: Foreign keys.
-// - ImageLinks: Array of strings. `gorm:"type:json"` specifies a JSON array type.
-// - Lecturer/TimeAllowed/DocLink/Tips: Nullable fields.
+// - Images: One-to-many relationship with QuestionImage, ordered by Page when preloaded.
+// - Solutions: One-to-many relationship with Solution; only approved ones are preloaded for the detail view.
+// - ImageLinks: Not stored; filled from Images after a find so responses keep exposing imageLinks.
+// - Lecturer/TimeAllowed/DocLink/Tips: Nullable fields. Lecturer and Tips share a FULLTEXT index for search.
 // - Type: Mapped to custom QuestionType enum.
+// - Paper/PaperLabel: Sequence of the paper among those of the same course, session and type (e.g. Test 1, Test 2),
+//   with an optional display label. The sequence is part of the ID.
 // - Downloads/Views: Integer fields with default 0.
 // - Approved: Boolean with default false.
+// - ModerationStatus: PENDING until an admin approves or rejects the question; kept in sync with Approved.
+// - ModerationReason/ModeratedAt: Reason and time of the latest moderation decision, visible to the uploader.
 // - ProcessingStatus: Track image processing status.
 // - CreatedAt/UpdatedAt: Automatically managed timestamps.
 // - Course/Session/Uploader: Many-to-one relationships.
@@ -137,15 +200,24 @@ type Question struct {
 	Course           *Course      `gorm:"foreignKey:CourseID" json:"course,omitempty"`
 	SessionID        string       `gorm:"type:char(10)" json:"sessionId"`
 	Session          *Session     `gorm:"foreignKey:SessionID" json:"session,omitempty"`
-	ImageLinks       []string     `gorm:"type:json;serializer:json" json:"imageLinks,omitempty"`
-	Lecturer         *string      `json:"lecturer,omitempty"`
+	Images           []QuestionImage `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"images,omitempty"`
+	Solutions        []Solution   `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"solutions,omitempty"`
+	ImageLinks       []string     `gorm:"-" json:"imageLinks,omitempty"`
+	Lecturer         *string      `gorm:"index:idx_questions_search,class:FULLTEXT" json:"lecturer,omitempty"`
 	TimeAllowed      *int         `json:"timeAllowed,omitempty"`
 	DocLink          *string      `json:"docLink,omitempty"`
-	Tips             *string      `json:"tips,omitempty"`
-	Type             QuestionType `json:"type"`
+	Tips             *string      `gorm:"index:idx_questions_search,class:FULLTEXT" json:"tips,omitempty"`
+	Type             QuestionType `gorm:"type:varchar(16);index" json:"type"`
+	Paper            int          `gorm:"default:1" json:"paper"`
+	PaperLabel       *string      `gorm:"type:varchar(50)" json:"paperLabel,omitempty"`
 	Downloads        *int         `gorm:"default:0" json:"downloads,omitempty"`
 	Views            *int         `gorm:"default:0" json:"views,omitempty"`
+	RatingSummary    `gorm:"embedded"`
+	MyRating         *QuestionRating `gorm:"-" json:"myRating,omitempty"`
 	Approved         bool         `gorm:"default:false" json:"approved"`
+	ModerationStatus ModerationStatus `gorm:"type:enum('PENDING','APPROVED','REJECTED');default:'PENDING';index" json:"moderationStatus"`
+	ModerationReason *string      `gorm:"type:text" json:"moderationReason,omitempty"`
+	ModeratedAt      *time.Time   `json:"moderatedAt,omitempty"`
 	ProcessingStatus *string      `gorm:"default:'pending'" json:"processingStatus,omitempty"`
 	UploaderID       *string      `gorm:"type:char(36)" json:"uploaderId,omitempty"`
 	Uploader         *User        `gorm:"foreignKey:UploaderID" json:"uploader,omitempty"`
@@ -153,23 +225,68 @@ type Question struct {
 	UpdatedAt        time.Time    `gorm:"autoUpdateTime" json:"updatedAt"`
 }
 
+// AfterFind fills ImageLinks from the preloaded Images
+func (q *Question) AfterFind(tx *gorm.DB) error {
+	q.SyncImageLinks()
+	return nil
+}
+
+// SyncImageLinks rebuilds ImageLinks from Images, keeping their order
+func (q *Question) SyncImageLinks() {
+	if len(q.Images) == 0 {
+		q.ImageLinks = nil
+		return
+	}
+
+	q.ImageLinks = make([]string, len(q.Images))
+	for i, image := range q.Images {
+		q.ImageLinks[i] = image.URL
+	}
+}
+
+// ImageAsset is the stored file behind a question or solution page.
+// Explanation:
+// - PublicID: Storage key (e.g. qb_questions/<questionID>/<file>), needed to move, retag or destroy the asset.
+// - Width/Height/Bytes/Format: Asset metadata reported by storage; zero for pages migrated from bare links.
+type ImageAsset struct {
+	PublicID string `gorm:"type:varchar(255)" json:"publicId"`
+	URL      string `gorm:"type:text" json:"url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Bytes    int    `json:"bytes"`
+	Format   string `gorm:"type:varchar(16)" json:"format"`
+}
+
+// QuestionImage is a single stored page of a question.
+// Explanation:
+// - Page: 1-based position of the page within the question.
+// - ImageAsset: Embedded, so its columns live on this table.
+type QuestionImage struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	QuestionID string `gorm:"type:char(36);index:idx_question_images_page,priority:1" json:"questionId"`
+	Page       int    `gorm:"index:idx_question_images_page,priority:2" json:"page"`
+	ImageAsset
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
 // Course model translated from Prisma schema.
 // Explanation:
 // - ID: The 6-character course code (e.g., "CEG543") used as primary key
 // - Units/Semester/LevelID: Integer fields.
 // - Description: Nullable string.
+// - ID/Title/Description: Share a FULLTEXT index for search.
 // - Status: Mapped to custom CourseStatus enum.
 // - CreatedAt/UpdatedAt: Automatically managed timestamps.
 // - Level: Many-to-one relationship with Level.
 // - Questions: One-to-many relationship with Question.
 // - Departments: Many-to-many relationship, using a join table.
 type Course struct {
-	ID            string       `gorm:"primaryKey;type:varchar(6)" json:"id"`
+	ID            string       `gorm:"primaryKey;type:varchar(6);index:idx_courses_search,class:FULLTEXT" json:"id"`
 	Units         int          `json:"units" binding:"required" validate:"required,min=1,max=10"`
-	Title         string       `json:"title" binding:"required" validate:"required"`
+	Title         string       `gorm:"index:idx_courses_search,class:FULLTEXT" json:"title" binding:"required" validate:"required"`
 	LevelID       int          `json:"levelId" binding:"required" validate:"required"`
 	Semester      int          `json:"semester" binding:"required" validate:"required,min=1,max=2"`
-	Description   *string      `json:"description,omitempty"`
+	Description   *string      `gorm:"index:idx_courses_search,class:FULLTEXT" json:"description,omitempty"`
 	Status        *CourseStatus `json:"status,omitempty" gorm:"default:'ELECTIVE'"`
 	CreatedAt     time.Time    `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt     time.Time    `gorm:"autoUpdateTime" json:"updatedAt"`
@@ -185,3 +302,224 @@ type TemporaryUpload struct {
 	ExpiresAt time.Time `gorm:"index" json:"expiresAt"`
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
 }
+
+// ResumableUpload tracks one file sent in chunks through the tus endpoint until it is staged in storage.
+// Explanation:
+// - ID: UUID used in the upload URL, so uploads can't be guessed.
+// - RequestID: The TemporaryUpload the file joins once complete; files sent together share it.
+// - Position: Order of the file within its request, which is the page order CreateQuestion receives.
+// - Length/Offset: Declared size and bytes received so far; the upload is complete when they match.
+// - PublicID/Error: Set once the finished file has been staged, or has failed to be.
+type ResumableUpload struct {
+	ID         string    `gorm:"primaryKey;type:char(36)" json:"id"`
+	RequestID  string    `gorm:"type:char(36);index" json:"requestId"`
+	UploaderID string    `gorm:"type:char(36);index" json:"uploaderId"`
+	Filename   string    `gorm:"type:varchar(255)" json:"filename"`
+	Position   int       `json:"position"`
+	Length     int64     `json:"length"`
+	Offset     int64     `json:"offset"`
+	PublicID   *string   `gorm:"type:varchar(255)" json:"publicId,omitempty"`
+	Error      *string   `gorm:"type:text" json:"error,omitempty"`
+	ExpiresAt  time.Time `gorm:"index" json:"expiresAt"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// ModerationEvent records a single change to a question's Approved flag, moderation status or processing status.
+// Explanation:
+// - ActorID: The user who made the change; nil when the system did it.
+// - From*/To*: State before and after the change, so disputes can be settled from the log alone.
+// - Question: Follows the question when its ID changes; set to nil when it is deleted, so the audit trail outlives it.
+type ModerationEvent struct {
+	ID                   uint             `gorm:"primaryKey" json:"id"`
+	QuestionID           *string          `gorm:"type:char(36);index" json:"questionId,omitempty"`
+	Question             *Question        `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+	ActorID              *string          `gorm:"type:char(36);index" json:"actorId,omitempty"`
+	Actor                *User            `gorm:"foreignKey:ActorID;constraint:OnDelete:SET NULL;" json:"actor,omitempty"`
+	Action               ModerationAction `gorm:"type:varchar(16)" json:"action"`
+	FromApproved         bool             `json:"fromApproved"`
+	ToApproved           bool             `json:"toApproved"`
+	FromStatus           ModerationStatus `gorm:"type:varchar(16)" json:"fromStatus,omitempty"`
+	ToStatus             ModerationStatus `gorm:"type:varchar(16)" json:"toStatus"`
+	FromProcessingStatus *string          `json:"fromProcessingStatus,omitempty"`
+	ToProcessingStatus   *string          `json:"toProcessingStatus,omitempty"`
+	Reason               *string          `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt            time.Time        `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// EngagementEvent is an append-only record of one view, download or bookmark of a question.
+// Explanation:
+// - UserID: The signed-in user, nil for anonymous engagement or once the user is deleted.
+// - Question: Cascades so events follow the question when its ID changes or it is deleted.
+type EngagementEvent struct {
+	ID         uint64         `gorm:"primaryKey" json:"id"`
+	QuestionID string         `gorm:"type:char(36);index:idx_engagement_question_time" json:"questionId"`
+	Question   *Question      `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	UserID     *string        `gorm:"type:char(36);index" json:"userId,omitempty"`
+	User       *User          `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL;" json:"-"`
+	Type       EngagementType `gorm:"type:varchar(16)" json:"type"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime;index:idx_engagement_question_time" json:"createdAt"`
+}
+
+// QuestionDailyStat rolls engagement events up into per-question counts for one UTC day.
+// Explanation:
+// - QuestionID/Day: Composite primary key, so each event batch upserts into a single row.
+type QuestionDailyStat struct {
+	QuestionID string    `gorm:"primaryKey;type:char(36)" json:"questionId"`
+	Question   *Question `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Day        time.Time `gorm:"primaryKey;type:date;index" json:"day"`
+	Views      int       `gorm:"default:0" json:"views"`
+	Downloads  int       `gorm:"default:0" json:"downloads"`
+	Bookmarks  int       `gorm:"default:0" json:"bookmarks"`
+}
+
+// Report is a user's complaint about a question, queued for admins to review.
+// Explanation:
+// - ReporterID: The user who reported it; set to NULL if the user is deleted.
+// - Details: Free text; required when the reason is OTHER.
+// - Status: OPEN until an admin resolves or dismisses it; open reports count towards auto-hiding the question.
+// - ReviewerID/ReviewNote/ReviewedAt: The admin who closed the report, their note and when.
+type Report struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	QuestionID string       `gorm:"type:char(36);index:idx_reports_question_status,priority:1" json:"questionId"`
+	Question   *Question    `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"question,omitempty"`
+	ReporterID *string      `gorm:"type:char(36);index" json:"reporterId,omitempty"`
+	Reporter   *User        `gorm:"foreignKey:ReporterID;constraint:OnDelete:SET NULL;" json:"reporter,omitempty"`
+	Reason     ReportReason `gorm:"type:varchar(16)" json:"reason"`
+	Details    *string      `gorm:"type:text" json:"details,omitempty"`
+	Status     ReportStatus `gorm:"type:varchar(16);default:'OPEN';index:idx_reports_question_status,priority:2" json:"status"`
+	ReviewerID *string      `gorm:"type:char(36)" json:"reviewerId,omitempty"`
+	Reviewer   *User        `gorm:"foreignKey:ReviewerID;constraint:OnDelete:SET NULL;" json:"reviewer,omitempty"`
+	ReviewNote *string      `gorm:"type:text" json:"reviewNote,omitempty"`
+	ReviewedAt *time.Time   `json:"reviewedAt,omitempty"`
+	CreatedAt  time.Time    `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// QuestionRating is one user's difficulty rating and readable/complete votes for a question.
+// Explanation:
+// - UserID/QuestionID: Composite primary key, so each user has one rating per question and changes it in place.
+// - Difficulty/Readable/Complete: Each is optional, so a user can rate difficulty without voting on the scans.
+type QuestionRating struct {
+	UserID     string    `gorm:"primaryKey;type:char(36)" json:"userId"`
+	User       *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+	QuestionID string    `gorm:"primaryKey;type:char(36);index" json:"questionId"`
+	Question   *Question `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Difficulty *int      `gorm:"type:tinyint" json:"difficulty,omitempty"`
+	Readable   *bool     `json:"readable,omitempty"`
+	Complete   *bool     `json:"complete,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// RatingSummary holds the aggregates of a question's ratings, stored on the question so lists can sort by them.
+// Explanation:
+// - Difficulty: Mean difficulty rating, nil until someone rates it.
+// - ReadableYes/ReadableNo, CompleteYes/CompleteNo: Vote counts on the quality of the scans.
+type RatingSummary struct {
+	Difficulty      *float64 `gorm:"type:decimal(3,2)" json:"difficulty,omitempty"`
+	DifficultyVotes int      `gorm:"default:0" json:"difficultyVotes"`
+	ReadableYes     int      `gorm:"default:0" json:"readableYes"`
+	ReadableNo      int      `gorm:"default:0" json:"readableNo"`
+	CompleteYes     int      `gorm:"default:0" json:"completeYes"`
+	CompleteNo      int      `gorm:"default:0" json:"completeNo"`
+}
+
+// Solution is a worked answer to a question, moderated separately from the question itself.
+// Explanation:
+// - ID: UUID, so the storage folder qb_solutions/<id>/ never has to move.
+// - QuestionID: Follows the question through ON UPDATE CASCADE and goes with it on delete.
+// - AuthorID: The user who submitted the solution; set to NULL if the user is deleted.
+// - Body: Optional text or markdown; a solution needs a body, pages or both.
+// - Approved/ModerationStatus/ModerationReason/ModeratedAt: Same meaning as on Question.
+type Solution struct {
+	ID               string           `gorm:"primaryKey;type:char(36);default:(uuid())" json:"id"`
+	QuestionID       string           `gorm:"type:char(36);index" json:"questionId"`
+	AuthorID         *string          `gorm:"type:char(36);index" json:"authorId,omitempty"`
+	Author           *User            `gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL;" json:"author,omitempty"`
+	Body             *string          `gorm:"type:mediumtext" json:"body,omitempty"`
+	Images           []SolutionImage  `gorm:"foreignKey:SolutionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"images,omitempty"`
+	Approved         bool             `gorm:"default:false" json:"approved"`
+	ModerationStatus ModerationStatus `gorm:"type:enum('PENDING','APPROVED','REJECTED');default:'PENDING';index" json:"moderationStatus"`
+	ModerationReason *string          `gorm:"type:text" json:"moderationReason,omitempty"`
+	ModeratedAt      *time.Time       `json:"moderatedAt,omitempty"`
+	ProcessingStatus *string          `gorm:"default:'pending'" json:"processingStatus,omitempty"`
+	CreatedAt        time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time        `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// SolutionImage is a single stored page of a solution.
+// Explanation:
+// - Page: 1-based position of the page within the solution.
+type SolutionImage struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	SolutionID string `gorm:"type:char(36);index:idx_solution_images_page,priority:1" json:"solutionId"`
+	Page       int    `gorm:"index:idx_solution_images_page,priority:2" json:"page"`
+	ImageAsset
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// Comment is a discussion post on a question.
+// Explanation:
+// - ParentID: Nil for a top-level comment; replies always point at the top-level comment of their thread,
+//   so threads are one level deep.
+// - AuthorID: Set to NULL if the user is deleted, leaving the comment in place.
+// - EditedAt: Set when the author changes the body.
+// - RemovedAt/RemovedByAdmin: A removed comment that still has replies stays as an empty placeholder so the
+//   thread keeps its shape; comments without replies are deleted outright.
+// - ReplyCount: Not stored; filled by the listing queries.
+type Comment struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	QuestionID     string     `gorm:"type:char(36);index:idx_comments_thread,priority:1" json:"questionId"`
+	Question       *Question  `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	ParentID       *uint      `gorm:"index:idx_comments_thread,priority:2" json:"parentId,omitempty"`
+	Parent         *Comment   `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE;" json:"-"`
+	AuthorID       *string    `gorm:"type:char(36);index" json:"authorId,omitempty"`
+	Author         *User      `gorm:"foreignKey:AuthorID;constraint:OnDelete:SET NULL;" json:"author,omitempty"`
+	Body           string     `gorm:"type:text" json:"body"`
+	EditedAt       *time.Time `json:"editedAt,omitempty"`
+	RemovedAt      *time.Time `json:"removedAt,omitempty"`
+	RemovedByAdmin bool       `gorm:"default:false" json:"removedByAdmin,omitempty"`
+	ReplyCount     int        `gorm:"->;-:migration" json:"replyCount"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// Bookmark saves a question to a user's revision list.
+// Explanation:
+// - UserID/QuestionID: Composite primary key, so a question is bookmarked at most once per user.
+type Bookmark struct {
+	UserID     string    `gorm:"primaryKey;type:char(36)" json:"userId"`
+	User       *User     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;" json:"-"`
+	QuestionID string    `gorm:"primaryKey;type:char(36);index" json:"questionId"`
+	Question   *Question `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"question,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+// Collection is a named, ordered list of questions owned by a user.
+// Explanation:
+// - OwnerID/Name: Unique together, so a user can't have two collections with the same name.
+// - Shared: When true, anyone with the ID can read the collection.
+// - ItemCount: Not stored; filled by the listing query.
+type Collection struct {
+	ID          string           `gorm:"primaryKey;type:char(36);default:(uuid())" json:"id"`
+	OwnerID     string           `gorm:"type:char(36);uniqueIndex:idx_collections_owner_name,priority:1" json:"ownerId"`
+	Owner       *User            `gorm:"foreignKey:OwnerID;constraint:OnDelete:CASCADE;" json:"owner,omitempty"`
+	Name        string           `gorm:"type:varchar(100);uniqueIndex:idx_collections_owner_name,priority:2" json:"name"`
+	Description *string          `gorm:"type:text" json:"description,omitempty"`
+	Shared      bool             `gorm:"default:false" json:"shared"`
+	Items       []CollectionItem `gorm:"foreignKey:CollectionID;constraint:OnDelete:CASCADE;" json:"items,omitempty"`
+	ItemCount   int              `gorm:"->;-:migration" json:"itemCount"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// CollectionItem places a question in a collection.
+// Explanation:
+// - Position: 1-based order within the collection.
+type CollectionItem struct {
+	CollectionID string    `gorm:"primaryKey;type:char(36)" json:"collectionId"`
+	QuestionID   string    `gorm:"primaryKey;type:char(36);index" json:"questionId"`
+	Question     *Question `gorm:"foreignKey:QuestionID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"question,omitempty"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"addedAt"`
+}
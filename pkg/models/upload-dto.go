@@ -25,7 +25,9 @@ type UploadResponse struct {
 type CreateQuestionDTO struct {
 	CourseID      string       `json:"courseId" binding:"required" validate:"required,len=6"`
 	SessionID     string       `json:"sessionId" binding:"required" validate:"required"`
-	Type          QuestionType `json:"type" binding:"required" validate:"required,oneof=TEST EXAM"`
+	Type          QuestionType `json:"type" binding:"required" validate:"required,oneof=TEST EXAM RESIT QUIZ ASSIGNMENT PRACTICAL MOCK_EXAM"`
+	Paper         int          `json:"paper,omitempty" validate:"omitempty,min=1,max=20"` // Defaults to 1 for the first paper; required once a paper exists
+	PaperLabel    *string      `json:"paperLabel,omitempty" validate:"omitempty,max=50"`
 	Lecturer      *string      `json:"lecturer,omitempty"`
 	TimeAllowed   *int         `json:"timeAllowed,omitempty" validate:"omitempty,min=1,max=600"`
 	DocLink       *string      `json:"docLink,omitempty" validate:"omitempty,url"`
@@ -35,14 +37,66 @@ type CreateQuestionDTO struct {
 	UploadResults *UploadResponse `json:"uploadResults,omitempty"`
 }
 
+// UpdateQuestionDTO represents a partial update of question metadata; nil fields are left unchanged
+type UpdateQuestionDTO struct {
+	CourseID    *string       `json:"courseId,omitempty" validate:"omitempty,len=6"`
+	SessionID   *string       `json:"sessionId,omitempty" validate:"omitempty,min=1"`
+	Type        *QuestionType `json:"type,omitempty" validate:"omitempty,oneof=TEST EXAM RESIT QUIZ ASSIGNMENT PRACTICAL MOCK_EXAM"`
+	Paper       *int          `json:"paper,omitempty" validate:"omitempty,min=1,max=20"`
+	PaperLabel  *string       `json:"paperLabel,omitempty" validate:"omitempty,max=50"`
+	Lecturer    *string       `json:"lecturer,omitempty"`
+	TimeAllowed *int          `json:"timeAllowed,omitempty" validate:"omitempty,min=1,max=600"`
+	DocLink     *string       `json:"docLink,omitempty" validate:"omitempty,url"`
+	Tips        *string       `json:"tips,omitempty"`
+}
+
+// CreateSolutionDTO represents a worked solution submitted for a question; it needs a body, pages or both
+type CreateSolutionDTO struct {
+	Body          *string         `json:"body,omitempty" validate:"omitempty,max=50000"`
+	UploadResults *UploadResponse `json:"uploadResults,omitempty"`
+}
+
+// ReorderImagesDTO lists a question's current image IDs in their new order
+type ReorderImagesDTO struct {
+	ImageIDs []uint `json:"imageIds" binding:"required" validate:"required,min=1"`
+}
+
+// ReplaceImageDTO carries the staged upload that replaces a single page
+type ReplaceImageDTO struct {
+	UploadResults *UploadResponse `json:"uploadResults" binding:"required" validate:"required"`
+}
+
+// QuestionFilterDTO holds the query parameters accepted by the question listing
+type QuestionFilterDTO struct {
+	CourseID     string `form:"courseId"`
+	SessionID    string `form:"sessionId"`
+	Type         string `form:"type" validate:"omitempty,oneof=TEST EXAM RESIT QUIZ ASSIGNMENT PRACTICAL MOCK_EXAM"`
+	Paper        int    `form:"paper" validate:"omitempty,min=1,max=20"`
+	DepartmentID string `form:"departmentId" validate:"omitempty,max=3"`
+	FacultyID    int    `form:"facultyId" validate:"omitempty,min=1"`
+	Level        int    `form:"level" validate:"omitempty,oneof=100 200 300 400 500"`
+	Semester     int    `form:"semester" validate:"omitempty,oneof=1 2"`
+	Lecturer     string `form:"lecturer" validate:"omitempty,max=100"`
+	YearFrom     int    `form:"yearFrom" validate:"omitempty,min=1000,max=9999"`
+	YearTo       int    `form:"yearTo" validate:"omitempty,min=1000,max=9999"`
+	Sort         string `form:"sort" validate:"omitempty,oneof=views downloads createdAt difficulty readable complete"`
+	Order        string `form:"order" validate:"omitempty,oneof=asc desc"`
+	Page         int    `form:"page"`
+	Limit        int    `form:"limit"`
+	Cursor       string `form:"cursor" validate:"omitempty,max=512"`
+}
+
 // QuestionResponse represents the response after creating a question
 type QuestionResponse struct {
 	ID               string   `json:"id"`
 	CourseID         string   `json:"courseId"`
 	SessionID        string   `json:"sessionId"`
 	Type             QuestionType   `json:"type"`
+	Paper            int      `json:"paper"`
+	PaperLabel       *string  `json:"paperLabel,omitempty"`
 	ImageCount       int      `json:"imageCount"`
 	ImageLinks       []string `json:"imageLinks,omitempty"`
+	Images           []QuestionImage `json:"images,omitempty"`
 	ProcessingStatus string   `json:"processingStatus"`
 	Approved         bool     `json:"approved"`
 	CreatedAt        string   `json:"createdAt"`
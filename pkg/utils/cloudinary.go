@@ -0,0 +1,53 @@
+package utils
+
+import "strings"
+
+// PublicIDFromURL recovers the public ID from a Cloudinary delivery URL such as
+// https://res.cloudinary.com/<cloud>/image/upload/v123/qb_questions/<id>/<file>.jpg
+// Anything between /upload/ and the version segment (transformations) is skipped.
+func PublicIDFromURL(link string) string {
+	marker := "/upload/"
+	i := strings.Index(link, marker)
+	if i < 0 {
+		return ""
+	}
+
+	path := link[i+len(marker):]
+	segments := strings.Split(path, "/")
+	for j, segment := range segments {
+		if isVersionSegment(segment) {
+			path = strings.Join(segments[j+1:], "/")
+			break
+		}
+	}
+
+	if dot := strings.LastIndex(path, "."); dot > strings.LastIndex(path, "/") {
+		path = path[:dot]
+	}
+	return path
+}
+
+// FormatFromURL returns the lowercase file extension of a link, without the dot
+func FormatFromURL(link string) string {
+	if q := strings.IndexAny(link, "?#"); q >= 0 {
+		link = link[:q]
+	}
+	dot := strings.LastIndex(link, ".")
+	if dot < 0 || dot < strings.LastIndex(link, "/") {
+		return ""
+	}
+	return strings.ToLower(link[dot+1:])
+}
+
+// isVersionSegment reports whether a URL segment looks like v1712345678
+func isVersionSegment(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+	for _, r := range segment[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
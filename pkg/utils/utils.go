@@ -3,6 +3,7 @@ package utils
 import (
 	"log"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,25 @@ func GetEnvFatal(key string) string {
 	return value
 }
 
+// GetEnv returns the value of an optional environment variable, or fallback when it is unset
+func GetEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetEnvList returns the comma-separated values of an optional environment variable, or nil when it is unset
+func GetEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func LoadDotEnv() {
 	err := godotenv.Load(".env")
 	if err != nil {